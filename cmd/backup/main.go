@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"game-tracker/internal/backup"
+	"game-tracker/internal/config"
+	"game-tracker/internal/database"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  backup create  --user-id=UID [--out=FILE]")
+	fmt.Fprintln(os.Stderr, "  backup restore --file=FILE [--user-id=UID] [--new-ids]")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "create":
+		runCreate(os.Args[2:])
+	case "restore":
+		runRestore(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func connect(ctx context.Context) *database.Client {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := database.NewClient(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore client: %v", err)
+	}
+	return db
+}
+
+func runCreate(args []string) {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	userID := flags.String("user-id", "", "Firebase UID of the user to back up")
+	outPath := flags.String("out", "", "Output file; a .gz suffix compresses it (defaults to stdout)")
+	_ = flags.Parse(args)
+
+	if *userID == "" {
+		log.Fatal("Error: --user-id is required")
+	}
+
+	ctx := context.Background()
+	db := connect(ctx)
+	defer db.Close()
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	buffered := bufio.NewWriter(out)
+	var w io.Writer = buffered
+
+	var gz *gzip.Writer
+	if strings.HasSuffix(*outPath, ".gz") {
+		gz = gzip.NewWriter(buffered)
+		w = gz
+	}
+
+	summary, err := backup.Write(ctx, w, db, *userID)
+	if err != nil {
+		log.Fatalf("Backup failed: %v", err)
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			log.Fatalf("Failed to finish backup: %v", err)
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		log.Fatalf("Failed to write backup: %v", err)
+	}
+
+	log.Printf("Backup complete for user %s: %d games, %d status changes", *userID, summary.Games, summary.StatusChanges)
+}
+
+func runRestore(args []string) {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	filePath := flags.String("file", "", "Backup archive to restore (plain or .gz)")
+	userID := flags.String("user-id", "", "Firebase UID to restore into (defaults to the archive's user)")
+	newIDs := flags.Bool("new-ids", false, "Give restored games new IDs (always on when restoring into another user)")
+	_ = flags.Parse(args)
+
+	if *filePath == "" {
+		log.Fatal("Error: --file is required")
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		log.Fatalf("Failed to open backup: %v", err)
+	}
+	defer file.Close()
+
+	ctx := context.Background()
+	db := connect(ctx)
+	defer db.Close()
+
+	summary, err := backup.Restore(ctx, file, db, backup.RestoreOptions{
+		TargetUserID: *userID,
+		NewIDs:       *newIDs,
+	})
+	if err != nil {
+		if summary != nil {
+			log.Fatalf("Restore failed after %d games and %d status changes: %v", summary.Games, summary.StatusChanges, err)
+		}
+		log.Fatalf("Restore failed: %v", err)
+	}
+
+	log.Printf("Restore complete: %d games, %d status changes, %d settings", summary.Games, summary.StatusChanges, summary.Settings)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"game-tracker/internal/config"
+	"game-tracker/internal/database"
+	"game-tracker/internal/export"
+	"game-tracker/internal/model"
+)
+
+func main() {
+	userID := flag.String("user-id", "", "Firebase UID of the user whose library is exported")
+	formatName := flag.String("format", "csv", "Export format: csv or json")
+	outPath := flag.String("out", "", "Output file (defaults to stdout)")
+	flag.Parse()
+
+	if *userID == "" {
+		log.Fatal("Error: --user-id is required")
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx := context.Background()
+	db, err := database.NewClient(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore client: %v", err)
+	}
+	defer db.Close()
+
+	out := os.Stdout
+	if *outPath != "" {
+		out, err = os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer out.Close()
+	}
+
+	buffered := bufio.NewWriter(out)
+
+	writer, err := export.NewWriter(buffered, format)
+	if err != nil {
+		log.Fatalf("Failed to start export: %v", err)
+	}
+
+	count := 0
+	err = db.ForEachGame(ctx, *userID, func(game *model.Game) error {
+		if err := writer.Write(game); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Export failed after %d games: %v", count, err)
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Fatalf("Failed to finish export: %v", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		log.Fatalf("Failed to write output: %v", err)
+	}
+
+	// Logs go to stderr so stdout stays a clean export
+	log.Printf("Exported %d games for user %s (%s)", count, *userID, format)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"game-tracker/internal/config"
+	"game-tracker/internal/database"
+	"game-tracker/internal/igdb"
+	"game-tracker/internal/importer"
+	"game-tracker/internal/matcher"
+)
+
+func main() {
+	userID := flag.String("user-id", "", "Firebase UID for the target user")
+	formatName := flag.String("format", "csv", "Import format: csv, json, steam, "+strings.Join(importer.SourceNames(), ", "))
+	filePath := flag.String("file", "", "File to import")
+	commit := flag.Bool("commit", false, "Save the games (without it only a preview is printed)")
+	titleColumn := flag.String("title-column", "", "Column holding the game title (csv/json)")
+	statusColumn := flag.String("status-column", "", "Column holding the status (csv/json)")
+	datePlayedColumn := flag.String("date-played-column", "", "Column holding the date played (csv/json)")
+	igdbIDColumn := flag.String("igdb-id-column", "", "Column holding the IGDB ID (csv/json)")
+	flag.Parse()
+
+	if *userID == "" {
+		log.Fatal("Error: --user-id is required")
+	}
+	if *filePath == "" {
+		log.Fatal("Error: --file is required")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx := context.Background()
+	db, err := database.NewClient(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize Firestore client: %v", err)
+	}
+	defer db.Close()
+
+	igdbClient := igdb.NewClient(cfg.IGDB.ClientID, cfg.IGDB.ClientSecret)
+	imp := importer.New(db, igdbClient, matcher.New(db, igdbClient.Search, igdbClient.SearchAlternativeNames, cfg.Match.Threshold))
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		log.Fatalf("Failed to open import file: %v", err)
+	}
+	defer file.Close()
+
+	mapping := importer.ColumnMapping{
+		Title:      *titleColumn,
+		Status:     *statusColumn,
+		DatePlayed: *datePlayedColumn,
+		IGDBID:     *igdbIDColumn,
+	}
+
+	var rows []importer.Row
+	switch *formatName {
+	case "csv":
+		rows, err = importer.ParseCSV(file, mapping)
+	case "json":
+		rows, err = importer.ParseJSON(file, mapping)
+	case "steam":
+		var apps []importer.SteamApp
+		apps, err = importer.ParseSteamLibrary(file)
+		if err == nil {
+			log.Printf("Found %d Steam apps", len(apps))
+			rows, err = imp.SteamRows(ctx, apps)
+		}
+	default:
+		source, ok := importer.LookupSource(*formatName)
+		if !ok {
+			log.Fatalf("Error: unsupported format %q", *formatName)
+		}
+		rows, err = source.Parse(file)
+	}
+	if err != nil {
+		log.Fatalf("Failed to read import file: %v", err)
+	}
+
+	log.Printf("Read %d games from %s", len(rows), *filePath)
+
+	preview, err := imp.Preview(ctx, *userID, rows)
+	if err != nil {
+		log.Fatalf("Failed to build preview: %v", err)
+	}
+
+	// Accept the proposed matches, the CLI equivalent of confirming the preview in the app
+	accepted := make([]importer.Row, 0, len(preview.Rows))
+	for _, row := range preview.Rows {
+		switch {
+		case row.Error != "":
+			log.Printf("✗ Line %d: %s (%s)", row.Line, row.Title, row.Error)
+		case row.Duplicate != nil:
+			log.Printf("⊘ Line %d: %s (already in library as '%s')", row.Line, row.Title, row.Duplicate.Title)
+		case row.IGDBID > 0:
+			log.Printf("✓ Line %d: %s (IGDB ID: %d)", row.Line, row.Title, row.IGDBID)
+			accepted = append(accepted, row.Row)
+		case row.Match != nil:
+			log.Printf("✓ Line %d: %s -> %s (IGDB ID: %d)", row.Line, row.Title, row.Match.Name, row.Match.IGDBID)
+			accepted = append(accepted, acceptMatch(row))
+		default:
+			log.Printf("? Line %d: %s (%d candidates, left for the background worker to match)", row.Line, row.Title, len(row.Candidates))
+			accepted = append(accepted, row.Row)
+		}
+	}
+
+	log.Printf("\nPreview: %d total, %d matched, %d unmatched, %d duplicates, %d errors",
+		preview.Total, preview.Matched, preview.Unmatched, preview.Duplicates, preview.Errors)
+
+	if !*commit {
+		log.Printf("Dry run only. Re-run with --commit to save %d games.", len(accepted))
+		return
+	}
+
+	result, err := imp.Commit(ctx, *userID, accepted)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	log.Printf("\nImport complete!")
+	log.Printf("Created: %d games", len(result.Created))
+	log.Printf("Skipped duplicates: %d", len(result.Skipped))
+	log.Printf("Errors: %d", len(result.Errors))
+}
+
+func acceptMatch(row importer.PreviewRow) importer.Row {
+	accepted := row.Row
+	accepted.IGDBID = row.Match.IGDBID
+	return accepted
+}
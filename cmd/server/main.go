@@ -19,7 +19,10 @@ import (
 	"game-tracker/internal/cache"
 	"game-tracker/internal/config"
 	"game-tracker/internal/database"
+	"game-tracker/internal/events"
 	"game-tracker/internal/igdb"
+	"game-tracker/internal/notify"
+	"game-tracker/internal/webhooks"
 	"game-tracker/internal/worker"
 )
 
@@ -60,17 +63,53 @@ func main() {
 
 	log.Println("Successfully connected to Firestore")
 
+	// Pushes game changes from any process (this server, other instances, the worker) to
+	// the users' open event streams, through a Firestore listener per connected user
+	broker := events.NewBroker(api.GameWatcher(db))
+
 	igdbClient := igdb.NewClient(cfg.IGDB.ClientID, cfg.IGDB.ClientSecret)
 	log.Println("IGDB client initialized")
 
 	searchCache := cache.NewCache(500, 1*time.Hour)
 	log.Println("Search cache initialized")
 
+	notifier := notify.NewService(db, notify.Config{
+		SMTP: notify.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		},
+		MaxAttempts:     cfg.Notify.MaxAttempts,
+		Timeout:         cfg.Notify.Timeout,
+		AllowedNetworks: cfg.Outbound.AllowedNetworks,
+	})
+
+	dispatcher := webhooks.NewDispatcher(db, webhooks.Options{
+		Senders:         cfg.Webhooks.Senders,
+		MaxAttempts:     cfg.Webhooks.MaxAttempts,
+		Timeout:         cfg.Webhooks.Timeout,
+		AllowedNetworks: cfg.Outbound.AllowedNetworks,
+	})
+	dispatcher.Start(ctx)
+
+	// The worker also serves admin-triggered runs, so it exists even with NO_SYNC
+	syncWorker := worker.New(db, igdbClient, worker.Options{
+		Interval:       cfg.Sync.Interval,
+		Concurrency:    cfg.Sync.Concurrency,
+		GameTimeout:    cfg.Sync.GameTimeout,
+		MaxFailures:    cfg.Sync.MaxFailures,
+		LeaseTTL:       cfg.Sync.LeaseTTL,
+		MatchThreshold: cfg.Match.Threshold,
+		Notifier:       notifier,
+		Webhooks:       dispatcher,
+	})
 	if !cfg.Server.NoSync {
-		go worker.StartBackgroundSync(ctx, db, igdbClient)
+		go syncWorker.StartBackgroundSync(ctx)
 	}
 
-	handler := api.NewHandler(db, igdbClient, searchCache, authClient)
+	handler := api.NewHandler(db, igdbClient, searchCache, authClient, syncWorker, notifier, dispatcher, broker, cfg.Admin.UserIDs, cfg.Match.Threshold)
 
 	mux := http.NewServeMux()
 
@@ -99,6 +138,8 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Event streams stay open until closed; end them so shutdown doesn't wait on them
+	server.RegisterOnShutdown(broker.Close)
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
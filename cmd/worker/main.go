@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"game-tracker/internal/config"
+	"game-tracker/internal/database"
+	"game-tracker/internal/igdb"
+	"game-tracker/internal/model"
+	"game-tracker/internal/notify"
+	"game-tracker/internal/webhooks"
+	"game-tracker/internal/worker"
+)
+
+func main() {
+	once := flag.Bool("once", false, "Run a single sync and exit (non-zero if any game failed), for cron or Cloud Scheduler")
+	backfill := flag.Bool("backfill-sync-schedule", false, "Give matched games saved by older versions a sync schedule and exit; run once after upgrading")
+	backfillReleases := flag.Bool("backfill-release-days", false, "Record the release days of games saved by older versions and exit; run once after upgrading")
+	flag.Parse()
+
+	os.Exit(run(*once, *backfill, *backfillReleases))
+}
+
+func run(once, backfill, backfillReleases bool) int {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("Failed to load configuration: %v", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.NewClient(ctx, cfg)
+	if err != nil {
+		log.Printf("Failed to initialize Firestore client: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	if backfill {
+		updated, err := db.BackfillSyncSchedule(ctx, time.Now())
+		if err != nil {
+			log.Printf("Backfill failed after %d games: %v", updated, err)
+			return 1
+		}
+		fmt.Printf("Scheduled %d games for sync\n", updated)
+		return 0
+	}
+
+	if backfillReleases {
+		updated, err := db.BackfillReleaseDays(ctx)
+		if err != nil {
+			log.Printf("Backfill failed after %d games: %v", updated, err)
+			return 1
+		}
+		fmt.Printf("Recorded release days of %d games\n", updated)
+		return 0
+	}
+
+	igdbClient := igdb.NewClient(cfg.IGDB.ClientID, cfg.IGDB.ClientSecret)
+
+	notifier := notify.NewService(db, notify.Config{
+		SMTP: notify.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		},
+		MaxAttempts:     cfg.Notify.MaxAttempts,
+		Timeout:         cfg.Notify.Timeout,
+		AllowedNetworks: cfg.Outbound.AllowedNetworks,
+	})
+
+	dispatcher := webhooks.NewDispatcher(db, webhooks.Options{
+		Senders:         cfg.Webhooks.Senders,
+		MaxAttempts:     cfg.Webhooks.MaxAttempts,
+		Timeout:         cfg.Webhooks.Timeout,
+		AllowedNetworks: cfg.Outbound.AllowedNetworks,
+	})
+	dispatcher.Start(ctx)
+
+	syncWorker := worker.New(db, igdbClient, worker.Options{
+		Interval:       cfg.Sync.Interval,
+		Concurrency:    cfg.Sync.Concurrency,
+		GameTimeout:    cfg.Sync.GameTimeout,
+		MaxFailures:    cfg.Sync.MaxFailures,
+		LeaseTTL:       cfg.Sync.LeaseTTL,
+		MatchThreshold: cfg.Match.Threshold,
+		Notifier:       notifier,
+		Webhooks:       dispatcher,
+	})
+
+	if !once {
+		syncWorker.StartBackgroundSync(ctx)
+		return 0
+	}
+
+	syncRun, err := syncWorker.RunOnceAsLeader(ctx)
+	if err != nil {
+		log.Printf("Sync failed: %v", err)
+		return 1
+	}
+	if syncRun == nil {
+		log.Println("Another instance is already syncing; nothing to do")
+		return 0
+	}
+
+	printSummary(syncRun)
+
+	// Send the run's webhooks before exiting; any that don't make it stay pending for
+	// the next process running the dispatcher
+	drainCtx, cancel := context.WithTimeout(ctx, 2*cfg.Webhooks.Timeout)
+	dispatcher.Drain(drainCtx)
+	cancel()
+
+	if syncRun.Status != model.SyncRunCompleted || syncRun.Errors > 0 || ctx.Err() != nil {
+		return 1
+	}
+	return 0
+}
+
+func printSummary(run *model.SyncRun) {
+	fmt.Printf("Sync run %s: %s\n", run.ID, run.Status)
+	if run.Error != "" {
+		fmt.Printf("  error:     %s\n", run.Error)
+	}
+	fmt.Printf("  synced:    %d\n", run.Synced)
+	fmt.Printf("  unchanged: %d\n", run.Unchanged)
+	fmt.Printf("  matched:   %d\n", run.Matched)
+	fmt.Printf("  multiple:  %d\n", run.Multiple)
+	fmt.Printf("  no match:  %d\n", run.NoMatch)
+	fmt.Printf("  errors:    %d\n", run.Errors)
+
+	for _, gameErr := range run.GameErrors {
+		fmt.Printf("    %s (%s): %s\n", gameErr.Title, gameErr.GameID, gameErr.Error)
+	}
+	if omitted := run.Errors - len(run.GameErrors); omitted > 0 {
+		fmt.Printf("    ... and %d more\n", omitted)
+	}
+}
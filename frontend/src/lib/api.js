@@ -23,6 +23,14 @@ export const api = {
     return response.json()
   },
 
+  // The full game, including the summary, storyline, screenshots and videos lists leave out
+  async getGame(gameId) {
+    const headers = await getAuthHeaders()
+    const response = await fetch(`${API_URL}/api/v1/games/${gameId}`, { headers })
+    if (!response.ok) throw new Error('Failed to fetch game')
+    return response.json()
+  },
+
   async createGame(gameData) {
     const headers = await getAuthHeaders()
     const response = await fetch(`${API_URL}/api/v1/games`, {
@@ -83,5 +91,32 @@ export const api = {
       throw new Error(errorText || 'Failed to update game match')
     }
     return response.json()
+  },
+
+  async updatePreferredPlatform(gameId, platform) {
+    const headers = await getAuthHeaders()
+    const response = await fetch(`${API_URL}/api/v1/games/${gameId}/platform`, {
+      method: 'POST',
+      headers,
+      body: JSON.stringify({ platform })
+    })
+    if (!response.ok) {
+      const errorText = await response.text()
+      throw new Error(errorText || 'Failed to update preferred platform')
+    }
+    return response.json()
+  },
+
+  // Opens the server-sent event stream of game changes; the caller reads the body
+  async openEventStream(lastEventId = '', signal) {
+    const headers = await getAuthHeaders()
+    headers['Accept'] = 'text/event-stream'
+    delete headers['Content-Type']
+    if (lastEventId) {
+      headers['Last-Event-ID'] = lastEventId
+    }
+    const response = await fetch(`${API_URL}/api/v1/events`, { headers, signal, cache: 'no-store' })
+    if (!response.ok) throw new Error('Failed to open event stream')
+    return response
   }
 }
@@ -12,6 +12,24 @@ export function isDatePlayedSentinel(dateString) {
   return date.getTime() === 0 || (date.getFullYear() === 1970 && date.getMonth() === 0 && date.getDate() === 1)
 }
 
+// The release on the platform the user plays on when the API resolved one, else the game's own release
+export function gameRelease(game) {
+  return game.platform_release || { date: game.release_date, precision: game.release_precision, label: game.release_label }
+}
+
+export function gameReleaseDate(game) {
+  return gameRelease(game).date || game.release_date
+}
+
+// Imprecise releases show IGDB's label ("Q3 2025", "2026") instead of a made-up day
+export function formatGameReleaseDate(game, options) {
+  const release = gameRelease(game)
+  if (release.label && release.precision && release.precision !== 'day') {
+    return release.label
+  }
+  return formatReleaseDate(gameReleaseDate(game), options)
+}
+
 export function formatReleaseDate(dateString, options = { year: 'numeric', month: 'long', day: 'numeric' }) {
   if (!dateString || isReleaseDateSentinel(dateString)) {
     return 'TBD'
@@ -0,0 +1,83 @@
+import { api } from './api'
+
+const MIN_RETRY_MS = 1000
+const MAX_RETRY_MS = 60000
+
+// Keeps a server-sent event stream of game changes open, reconnecting with backoff and
+// resuming from the last event received. EventSource can't send the Authorization
+// header, so the stream is read with fetch. Returns a function that closes it.
+export function connectEvents(onEvent) {
+  const controller = new AbortController()
+  let lastEventId = ''
+  let retryMs = MIN_RETRY_MS
+  let serverRetryMs = null
+
+  const dispatch = (event) => {
+    if (event.id) lastEventId = event.id
+    if (!event.type) return
+
+    let data = {}
+    try {
+      data = event.data ? JSON.parse(event.data) : {}
+    } catch (e) {
+      console.error('Failed to parse event:', e)
+      return
+    }
+    onEvent(event.type, data)
+  }
+
+  const read = async () => {
+    const response = await api.openEventStream(lastEventId, controller.signal)
+    retryMs = MIN_RETRY_MS
+
+    const reader = response.body.pipeThrough(new TextDecoderStream()).getReader()
+    let buffer = ''
+    let event = { id: '', type: '', data: '' }
+
+    while (true) {
+      const { value, done } = await reader.read()
+      if (done) return
+      buffer += value
+
+      const lines = buffer.split(/\r\n|\r|\n/)
+      buffer = lines.pop()
+      for (const line of lines) {
+        if (line === '') {
+          dispatch(event)
+          event = { id: '', type: '', data: '' }
+          continue
+        }
+        if (line.startsWith(':')) continue
+
+        const colon = line.indexOf(':')
+        const field = colon === -1 ? line : line.slice(0, colon)
+        let fieldValue = colon === -1 ? '' : line.slice(colon + 1)
+        if (fieldValue.startsWith(' ')) fieldValue = fieldValue.slice(1)
+
+        if (field === 'id') event.id = fieldValue
+        else if (field === 'event') event.type = fieldValue
+        else if (field === 'data') event.data = event.data ? `${event.data}\n${fieldValue}` : fieldValue
+        else if (field === 'retry' && /^\d+$/.test(fieldValue)) serverRetryMs = Number(fieldValue)
+      }
+    }
+  }
+
+  const run = async () => {
+    while (!controller.signal.aborted) {
+      try {
+        await read()
+      } catch (e) {
+        if (controller.signal.aborted) return
+        console.warn('Event stream disconnected:', e.message)
+        retryMs = Math.min(retryMs * 2, MAX_RETRY_MS)
+      }
+      if (controller.signal.aborted) return
+
+      const delay = Math.max(retryMs, serverRetryMs ?? 0)
+      await new Promise(resolve => setTimeout(resolve, delay))
+    }
+  }
+
+  run()
+  return () => controller.abort()
+}
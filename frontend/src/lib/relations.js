@@ -0,0 +1,33 @@
+// Whether the game can only be played with its base game (parent_game); the server
+// decides which relations count, see GameRelation.needs_parent
+export function isAddOn(game) {
+  return !!game.relation?.needs_parent
+}
+
+// Splits a list into top-level games and the add-ons whose base game is in the same
+// list, keyed by the base game's ID, so views can show DLC under its base game
+export function groupAddOns(games) {
+  const idsByIgdbId = new Map()
+  for (const game of games) {
+    if (game.igdb_id) idsByIgdbId.set(game.igdb_id, game.id)
+  }
+
+  const topLevel = []
+  const addOns = {}
+  for (const game of games) {
+    const baseId = isAddOn(game) ? idsByIgdbId.get(game.parent_game.igdb_id) : null
+    if (baseId && baseId !== game.id) {
+      (addOns[baseId] ||= []).push(game)
+    } else {
+      topLevel.push(game)
+    }
+  }
+
+  return { games: topLevel, addOns }
+}
+
+// Warns after adding DLC or an expansion whose base game isn't in the library
+export function warnIfBaseGameMissing(game) {
+  if (!game.relation || game.relation.parent_in_library || !isAddOn(game)) return
+  window.$toast?.warning(`${game.title} is ${game.relation.label}, which isn't in your library`, 6000)
+}
@@ -1,18 +1,19 @@
 import { defineStore } from 'pinia'
 import { ref } from 'vue'
 import { api } from '../lib/api'
+import { gameReleaseDate } from '../lib/dateUtils'
 
 const sortByReleaseDate = (games, descending = false) => {
   return games.sort((a, b) => {
-    const dateA = a.release_date ? new Date(a.release_date) : new Date(0)
-    const dateB = b.release_date ? new Date(b.release_date) : new Date(0)
+    const dateA = gameReleaseDate(a) ? new Date(gameReleaseDate(a)) : new Date(0)
+    const dateB = gameReleaseDate(b) ? new Date(gameReleaseDate(b)) : new Date(0)
     return descending ? dateB - dateA : dateA - dateB
   })
 }
 
 const shouldAddToCalendar = (game) => {
-  if (!game.release_date) return false
-  const releaseDate = new Date(game.release_date)
+  if (!gameReleaseDate(game)) return false
+  const releaseDate = new Date(gameReleaseDate(game))
   const oneMonthAgo = new Date()
   oneMonthAgo.setMonth(oneMonthAgo.getMonth() - 1)
   return releaseDate >= oneMonthAgo
@@ -165,6 +166,56 @@ export const useGamesStore = defineStore('games', () => {
     }
   }
 
+  // Moves a game changed elsewhere (another tab or device, or the background sync)
+  // into the right lists
+  function applyGameChange(game) {
+    removeGameFromAllLists(game.id, lists)
+    addGameToStatusList(game, lists)
+
+    all.value = all.value.filter(g => g.id !== game.id)
+    all.value.push(game)
+    sortByReleaseDate(all.value, true)
+  }
+
+  function findGame(gameId) {
+    for (const list of [all, backlog, playing, history, calendar]) {
+      const game = list.value.find(g => g.id === gameId)
+      if (game) return game
+    }
+    return null
+  }
+
+  // Applies an event from the server's game change stream
+  function handleServerEvent(type, data) {
+    switch (type) {
+      case 'game.created':
+      case 'game.updated':
+        applyGameChange(data)
+        break
+      case 'game.status_changed': {
+        const game = findGame(data.game_id)
+        if (!game) break
+        applyGameChange({
+          ...game,
+          status: data.status,
+          date_played: data.date_played ?? game.date_played,
+          updated_at: data.updated_at
+        })
+        break
+      }
+      case 'game.deleted':
+        removeGameFromAllLists(data.game_id, lists)
+        all.value = all.value.filter(g => g.id !== data.game_id)
+        break
+      case 'reset':
+        // Changes were missed; reload every list
+        for (const view of ['all', 'backlog', 'playing', 'history', 'calendar']) {
+          fetchGames(view)
+        }
+        break
+    }
+  }
+
   async function updateGameMatch(gameId, igdbId) {
     try {
       const updatedGame = await api.updateGameMatch(gameId, igdbId)
@@ -202,6 +253,7 @@ export const useGamesStore = defineStore('games', () => {
     updateStatus,
     searchIGDB,
     deleteGame,
-    updateGameMatch
+    updateGameMatch,
+    handleServerEvent
   }
 })
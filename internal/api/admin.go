@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"game-tracker/internal/middleware"
+	"game-tracker/internal/model"
+)
+
+const (
+	defaultSyncRunsLimit = 20
+	maxSyncRunsLimit     = 100
+)
+
+// ErrSyncQueued is returned by Syncer.QueueSync while another run is waiting to start
+var ErrSyncQueued = errors.New("a sync run is already queued")
+
+// Syncer runs the background sync on demand (implemented by worker.Worker, which imports this package)
+type Syncer interface {
+	// QueueSync records a run for the instance holding the sync lease to start
+	QueueSync(ctx context.Context, scope model.SyncScope, requestedBy string) (*model.SyncRun, error)
+	RefreshGame(ctx context.Context, game *model.Game) (bool, error)
+}
+
+// AdminSyncRequest optionally narrows a triggered run to one user or one game
+type AdminSyncRequest struct {
+	UserID string `json:"user_id,omitempty"`
+	GameID string `json:"game_id,omitempty"`
+}
+
+// handleAdminSync handles POST /api/v1/admin/sync, queueing a run for the sync leader and
+// returning its record
+func (h *Handler) handleAdminSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req AdminSyncRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.UserID != "" && req.GameID != "" {
+		http.Error(w, "Specify user_id or game_id, not both", http.StatusBadRequest)
+		return
+	}
+
+	if req.GameID != "" {
+		if _, err := h.db.GetGame(r.Context(), req.GameID); err != nil {
+			http.Error(w, "Game not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	scope := model.SyncScope{UserID: req.UserID, GameID: req.GameID}
+	run, err := h.syncer.QueueSync(r.Context(), scope, adminID)
+	if errors.Is(err, ErrSyncQueued) {
+		http.Error(w, "A sync run is already queued", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to queue sync run: %v", err)
+		http.Error(w, "Failed to queue sync run", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Admin %s queued sync run %s", adminID, run.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(run); err != nil {
+		log.Printf("ERROR: Failed to encode JSON response: %v", err)
+	}
+}
+
+// handleAdminSyncRuns handles GET /api/v1/admin/sync/runs?limit=N, newest first
+func (h *Handler) handleAdminSyncRuns(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := defaultSyncRunsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxSyncRunsLimit {
+			http.Error(w, "limit must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.db.GetRecentSyncRuns(r.Context(), limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch sync runs: %v", err)
+		http.Error(w, "Failed to fetch sync runs", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, runs)
+}
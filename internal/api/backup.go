@@ -0,0 +1,102 @@
+package api
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"game-tracker/internal/backup"
+	"game-tracker/internal/database"
+	"game-tracker/internal/middleware"
+)
+
+// Restore limits: the upload, the archive once decompressed, and the records held in
+// memory while it is validated
+const (
+	maxRestoreBodyBytes    = 50 << 20
+	maxRestoreArchiveBytes = 256 << 20
+	maxRestoreRecords      = 200_000
+)
+
+// handleBackup handles GET /api/v1/backup, streaming a gzip-compressed archive of the user's data
+func (h *Handler) handleBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Warning: Could not extend write deadline for backup: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="game-tracker-backup-%s.jsonl.gz"`, time.Now().Format("2006-01-02")))
+
+	gz := gzip.NewWriter(w)
+	summary, err := backup.Write(r.Context(), gz, h.db, userID)
+	if err != nil {
+		// The archive has no trailer, so a truncated download is rejected on restore
+		log.Printf("ERROR: Backup failed for user %s: %v", userID, err)
+		return
+	}
+	if err := gz.Close(); err != nil {
+		log.Printf("ERROR: Failed to finish backup for user %s: %v", userID, err)
+		return
+	}
+
+	log.Printf("Backup written for user %s: %d games, %d status changes", userID, summary.Games, summary.StatusChanges)
+}
+
+// handleRestore handles POST /api/v1/restore with an archive body (plain or gzip).
+// Records are restored into the calling user; an archive from another user gets new IDs,
+// and records whose IDs belong to another user's documents are refused.
+func (h *Handler) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(10 * time.Minute)); err != nil {
+		log.Printf("Warning: Could not extend write deadline for restore: %v", err)
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxRestoreBodyBytes)
+	summary, err := backup.Restore(r.Context(), body, h.db, backup.RestoreOptions{
+		TargetUserID: userID,
+		MaxBytes:     maxRestoreArchiveBytes,
+		MaxRecords:   maxRestoreRecords,
+	})
+	if err != nil {
+		log.Printf("ERROR: Restore failed for user %s: %v", userID, err)
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, backup.ErrTooLarge) || errors.As(err, &maxBytesErr):
+			http.Error(w, "Backup archive is too large", http.StatusRequestEntityTooLarge)
+		case summary == nil:
+			http.Error(w, "Invalid backup archive: "+err.Error(), http.StatusBadRequest)
+		case errors.Is(err, database.ErrNotOwner):
+			http.Error(w, "Backup contains records that belong to another user", http.StatusForbidden)
+		default:
+			http.Error(w, "Restore failed partway through", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Printf("Backup restored for user %s: %d games, %d status changes", userID, summary.Games, summary.StatusChanges)
+	respondJSON(w, summary)
+}
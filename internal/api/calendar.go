@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"game-tracker/internal/middleware"
+	"game-tracker/internal/model"
+)
+
+// CalendarBucket is one heading of the release calendar
+type CalendarBucket struct {
+	Key       string              `json:"key"`                 // "recent", "2025-09", "2025-Q3", "2025" or "tbd"
+	Label     string              `json:"label"`               // e.g. "Recent Releases", "September 2025", "Q3 2025", "2025", "To Be Determined"
+	Precision model.DatePrecision `json:"precision,omitempty"` // month, quarter or year for date buckets
+	Games     []*model.Game       `json:"games"`
+}
+
+// handleCalendar handles GET /api/v1/calendar: the calendar view's games grouped into
+// buckets. Games released in the last month come first, then upcoming games by month;
+// games only known to a quarter or year get their own bucket after that period's last
+// month, and undated games come last.
+func (h *Handler) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	games, err := h.db.GetBacklog(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch games: %v", err)
+		http.Error(w, "Failed to fetch games", http.StatusInternalServerError)
+		return
+	}
+
+	settings, err := h.db.GetSettings(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch settings: %v", err)
+		http.Error(w, "Failed to fetch games", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	applyReleaseDates(games, settings.PreferredPlatform)
+	games = upcomingSince(games, now.AddDate(0, -1, 0))
+	sortByReleaseDate(games)
+
+	respondJSON(w, CalendarBuckets(listViews(games), now))
+}
+
+// CalendarBuckets groups games, with PlatformRelease filled in and sorted by it, into
+// calendar buckets in display order
+func CalendarBuckets(games []*model.Game, now time.Time) []CalendarBucket {
+	buckets := []CalendarBucket{}
+	index := make(map[string]int)
+
+	for _, game := range games {
+		bucket := calendarBucket(game.PlatformRelease, now)
+		i, ok := index[bucket.Key]
+		if !ok {
+			i = len(buckets)
+			index[bucket.Key] = i
+			buckets = append(buckets, bucket)
+		}
+		buckets[i].Games = append(buckets[i].Games, game)
+	}
+
+	// Games are sorted by release, so the buckets already run from recent releases to TBD.
+	// Undated games have nothing to order them by but their title.
+	if last := len(buckets) - 1; last >= 0 && buckets[last].Key == "tbd" {
+		tbd := buckets[last].Games
+		sort.SliceStable(tbd, func(i, j int) bool {
+			return tbd[i].Title < tbd[j].Title
+		})
+	}
+
+	return buckets
+}
+
+// calendarBucket picks the bucket for a release: exact days and months go under their
+// month, quarters and years under their own heading
+func calendarBucket(release *model.PlatformRelease, now time.Time) CalendarBucket {
+	precision := release.DatePrecision()
+	if precision == model.DatePrecisionTBD {
+		return CalendarBucket{Key: "tbd", Label: "To Be Determined"}
+	}
+	if release.PeriodEnd().Before(now) {
+		return CalendarBucket{Key: "recent", Label: "Recent Releases"}
+	}
+
+	date := release.Date.UTC()
+	switch precision {
+	case model.DatePrecisionQuarter:
+		quarter := (int(date.Month())-1)/3 + 1
+		return CalendarBucket{
+			Key:       fmt.Sprintf("%d-Q%d", date.Year(), quarter),
+			Label:     fmt.Sprintf("Q%d %d", quarter, date.Year()),
+			Precision: model.DatePrecisionQuarter,
+		}
+	case model.DatePrecisionYear:
+		return CalendarBucket{
+			Key:       fmt.Sprintf("%d", date.Year()),
+			Label:     fmt.Sprintf("%d", date.Year()),
+			Precision: model.DatePrecisionYear,
+		}
+	default:
+		return CalendarBucket{
+			Key:       date.Format("2006-01"),
+			Label:     date.Format("January 2006"),
+			Precision: model.DatePrecisionMonth,
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"game-tracker/internal/model"
+)
+
+// MatchCandidatesResponse lists the ranked IGDB candidates for a game
+type MatchCandidatesResponse struct {
+	MatchStatus model.MatchStatus      `json:"match_status"`
+	Reason      string                 `json:"reason,omitempty"`
+	Candidates  []model.MatchCandidate `json:"candidates"`
+}
+
+// getMatchCandidates handles GET /api/v1/games/{id}/candidates?refresh={true|false}.
+// It returns the candidates stored by the last automatic match; refresh=true (or a game
+// without stored candidates) runs the matcher again and stores the result.
+func (h *Handler) getMatchCandidates(w http.ResponseWriter, r *http.Request, userID, gameID string) {
+	game, err := h.db.GetGame(r.Context(), gameID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch game: %v", err)
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	if game.UserID != userID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if r.URL.Query().Get("refresh") == "true" || len(game.MatchCandidates) == 0 {
+		result, err := h.matcher.Match(r.Context(), userID, game.ID, game.MatchTitle())
+		if err != nil {
+			log.Printf("ERROR: Failed to match game %s: %v", gameID, err)
+			http.Error(w, "Failed to search IGDB", http.StatusInternalServerError)
+			return
+		}
+
+		game.MatchCandidates = result.TopCandidates()
+		game.MatchReason = result.Reason
+		if err := h.db.UpdateMatchCandidates(r.Context(), game); err != nil {
+			log.Printf("ERROR: Failed to store match candidates for game %s: %v", gameID, err)
+		}
+	}
+
+	candidates := game.MatchCandidates
+	if candidates == nil {
+		candidates = []model.MatchCandidate{}
+	}
+
+	respondJSON(w, MatchCandidatesResponse{
+		MatchStatus: game.MatchStatus,
+		Reason:      game.MatchReason,
+		Candidates:  candidates,
+	})
+}
+
+// UpdateSearchHintRequest sets the text used instead of the title when matching
+type UpdateSearchHintRequest struct {
+	SearchHint string `json:"search_hint"` // Empty clears the hint
+}
+
+// updateSearchHint handles POST /api/v1/games/{id}/search-hint. An unmatched game is
+// queued for matching on the next sync run instead of waiting out its retry backoff.
+func (h *Handler) updateSearchHint(w http.ResponseWriter, r *http.Request, userID, gameID string) {
+	var req UpdateSearchHintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.db.GetGame(r.Context(), gameID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch game: %v", err)
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	if game.UserID != userID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	game.SearchHint = strings.TrimSpace(req.SearchHint)
+	game.MatchAttempts = 0
+	game.NextMatchAt = nil
+	if game.IGDBID == 0 {
+		game.MatchStatus = model.MatchStatusUnmatched
+	}
+
+	if err := h.db.UpdateSearchHint(r.Context(), game); err != nil {
+		log.Printf("ERROR: Failed to update search hint for game %s: %v", gameID, err)
+		http.Error(w, "Failed to update search hint", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Search hint updated: %s (ID: %s) -> %q", game.Title, gameID, game.SearchHint)
+	respondJSON(w, game)
+}
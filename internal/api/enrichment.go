@@ -1,6 +1,7 @@
 package api
 
 import (
+	"slices"
 	"time"
 
 	"game-tracker/internal/igdb"
@@ -10,6 +11,29 @@ import (
 func EnrichGameFromIGDB(game *model.Game, igdbGame *igdb.Game, trackChanges bool) bool {
 	changed := false
 
+	// IGDB leaves out empty fields, so a new match starts from a clean slate instead of
+	// keeping the previous match's relations (a game moved off a DLC would stay "DLC of"),
+	// description and media. Time to beat comes with the next scheduled sync.
+	if !trackChanges {
+		game.TimeToBeat = nil
+		game.GameType = ""
+		game.ParentGame = nil
+		game.Franchises = nil
+		game.Collections = nil
+		game.DLCs = nil
+		game.Expansions = nil
+		game.Remasters = nil
+		game.Developers = nil
+		game.Publishers = nil
+		game.Summary = ""
+		game.Storyline = ""
+		game.Themes = nil
+		game.GameModes = nil
+		game.PlayerPerspectives = nil
+		game.Screenshots = nil
+		game.Videos = nil
+	}
+
 	if newValue := igdbGame.Name; !trackChanges || game.Title != newValue {
 		game.Title = newValue
 		changed = true
@@ -53,6 +77,15 @@ func EnrichGameFromIGDB(game *model.Game, igdbGame *igdb.Game, trackChanges bool
 		}
 	}
 
+	var newReleases []model.PlatformRelease
+	if len(igdbGame.ReleaseDates) > 0 {
+		newReleases = platformReleases(igdbGame.ReleaseDates)
+		if !trackChanges || !releasesEqual(game.Releases, newReleases) {
+			game.Releases = newReleases
+			changed = true
+		}
+	}
+
 	if igdbGame.FirstReleaseDate != nil {
 		releaseDate := time.Unix(*igdbGame.FirstReleaseDate, 0)
 		if !trackChanges || game.ReleaseDate == nil || !game.ReleaseDate.Equal(releaseDate) {
@@ -61,6 +94,17 @@ func EnrichGameFromIGDB(game *model.Game, igdbGame *igdb.Game, trackChanges bool
 		}
 	}
 
+	if precision, label := firstReleasePrecision(igdbGame.FirstReleaseDate, newReleases); !trackChanges || game.ReleasePrecision != precision || game.ReleaseLabel != label {
+		game.ReleasePrecision = precision
+		game.ReleaseLabel = label
+		changed = true
+	}
+
+	if newValue := igdbGame.URL; newValue != "" && (!trackChanges || game.IGDBURL != newValue) {
+		game.IGDBURL = newValue
+		changed = true
+	}
+
 	if len(igdbGame.Websites) > 0 {
 		newSteamURL := ""
 		newOfficialURL := ""
@@ -82,11 +126,134 @@ func EnrichGameFromIGDB(game *model.Game, igdbGame *igdb.Game, trackChanges bool
 		}
 	}
 
+	if igdbGame.TimeToBeat != nil {
+		if newValue := timeToBeat(igdbGame.TimeToBeat); !trackChanges || !timeToBeatEqual(game.TimeToBeat, newValue) {
+			game.TimeToBeat = newValue
+			changed = true
+		}
+	}
+
+	if igdbGame.GameType != nil {
+		if newValue := igdbGame.GameType.Type; !trackChanges || game.GameType != newValue {
+			game.GameType = newValue
+			changed = true
+		}
+	}
+
+	if igdbGame.ParentGame != nil {
+		newValue := &model.GameRef{IGDBID: igdbGame.ParentGame.ID, Name: igdbGame.ParentGame.Name}
+		if !trackChanges || game.ParentGame == nil || *game.ParentGame != *newValue {
+			game.ParentGame = newValue
+			changed = true
+		}
+	}
+
+	if newValue := franchiseNames(igdbGame); len(newValue) > 0 && (!trackChanges || !stringSlicesEqual(game.Franchises, newValue)) {
+		game.Franchises = newValue
+		changed = true
+	}
+
+	if len(igdbGame.Collections) > 0 {
+		newValue := make([]string, len(igdbGame.Collections))
+		for i, collection := range igdbGame.Collections {
+			newValue[i] = collection.Name
+		}
+		if !trackChanges || !stringSlicesEqual(game.Collections, newValue) {
+			game.Collections = newValue
+			changed = true
+		}
+	}
+
+	for _, related := range []struct {
+		field *[]model.GameRef
+		games [][]igdb.Game
+	}{
+		{&game.DLCs, [][]igdb.Game{igdbGame.DLCs}},
+		{&game.Expansions, [][]igdb.Game{igdbGame.Expansions, igdbGame.StandaloneExpansions}},
+		{&game.Remasters, [][]igdb.Game{igdbGame.Remasters, igdbGame.Remakes}},
+	} {
+		if newValue := gameRefs(related.games...); len(newValue) > 0 && (!trackChanges || !slices.Equal(*related.field, newValue)) {
+			*related.field = newValue
+			changed = true
+		}
+	}
+
+	if len(igdbGame.InvolvedCompanies) > 0 {
+		newDevelopers, newPublishers := companyNames(igdbGame.InvolvedCompanies)
+		if !trackChanges || !stringSlicesEqual(game.Developers, newDevelopers) {
+			game.Developers = newDevelopers
+			changed = true
+		}
+		if !trackChanges || !stringSlicesEqual(game.Publishers, newPublishers) {
+			game.Publishers = newPublishers
+			changed = true
+		}
+	}
+
+	if newValue := igdbGame.Summary; newValue != "" && (!trackChanges || game.Summary != newValue) {
+		game.Summary = newValue
+		changed = true
+	}
+
+	if newValue := igdbGame.Storyline; newValue != "" && (!trackChanges || game.Storyline != newValue) {
+		game.Storyline = newValue
+		changed = true
+	}
+
+	for _, tags := range []struct {
+		field *[]string
+		names []string
+	}{
+		{&game.Themes, names(igdbGame.Themes, func(theme igdb.Theme) string { return theme.Name })},
+		{&game.GameModes, names(igdbGame.GameModes, func(mode igdb.GameMode) string { return mode.Name })},
+		{&game.PlayerPerspectives, names(igdbGame.PlayerPerspectives, func(perspective igdb.PlayerPerspective) string { return perspective.Name })},
+	} {
+		if len(tags.names) > 0 && (!trackChanges || !stringSlicesEqual(*tags.field, tags.names)) {
+			*tags.field = tags.names
+			changed = true
+		}
+	}
+
+	if len(igdbGame.Screenshots) > 0 {
+		newValue := make([]string, 0, min(len(igdbGame.Screenshots), model.MaxScreenshots))
+		for _, screenshot := range igdbGame.Screenshots {
+			if screenshot.ImageID != "" && len(newValue) < model.MaxScreenshots {
+				newValue = append(newValue, screenshot.ScreenshotBigURL())
+			}
+		}
+		if !trackChanges || !stringSlicesEqual(game.Screenshots, newValue) {
+			game.Screenshots = newValue
+			changed = true
+		}
+	}
+
+	if len(igdbGame.Videos) > 0 {
+		newValue := make([]model.GameVideo, 0, min(len(igdbGame.Videos), model.MaxVideos))
+		for _, video := range igdbGame.Videos {
+			if video.VideoID != "" && len(newValue) < model.MaxVideos {
+				newValue = append(newValue, model.GameVideo{Name: video.Name, YouTubeID: video.VideoID})
+			}
+		}
+		if !trackChanges || !slices.Equal(game.Videos, newValue) {
+			game.Videos = newValue
+			changed = true
+		}
+	}
+
 	if trackChanges && game.LastSyncError != "" {
 		game.LastSyncError = ""
 		changed = true
 	}
 
+	// A fresh match starts with a clean sync record, re-enabling a game the worker gave up on
+	if !trackChanges {
+		game.LastSyncError = ""
+		game.SyncFailures = 0
+		game.SyncDisabled = false
+		game.MatchAttempts = 0
+		game.NextMatchAt = nil
+	}
+
 	return changed
 }
 
@@ -101,3 +268,173 @@ func stringSlicesEqual(a, b []string) bool {
 	}
 	return true
 }
+
+// franchiseNames lists the game's franchises, the main one first
+func franchiseNames(igdbGame *igdb.Game) []string {
+	var names []string
+	if igdbGame.Franchise != nil && igdbGame.Franchise.Name != "" {
+		names = append(names, igdbGame.Franchise.Name)
+	}
+	for _, franchise := range igdbGame.Franchises {
+		if franchise.Name != "" && !slices.Contains(names, franchise.Name) {
+			names = append(names, franchise.Name)
+		}
+	}
+	return names
+}
+
+// companyNames splits the companies behind a game into developers and publishers, in
+// IGDB's order. A company can be both.
+func companyNames(companies []igdb.InvolvedCompany) (developers, publishers []string) {
+	for _, involved := range companies {
+		if involved.Company == nil || involved.Company.Name == "" {
+			continue
+		}
+		name := involved.Company.Name
+		if involved.Developer && !slices.Contains(developers, name) {
+			developers = append(developers, name)
+		}
+		if involved.Publisher && !slices.Contains(publishers, name) {
+			publishers = append(publishers, name)
+		}
+	}
+	return developers, publishers
+}
+
+// names lists the non-empty names of IGDB items such as themes or game modes
+func names[T any](items []T, name func(T) string) []string {
+	var result []string
+	for _, item := range items {
+		if n := name(item); n != "" {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// gameRefs converts lists of related IGDB games to references, in order
+func gameRefs(lists ...[]igdb.Game) []model.GameRef {
+	var refs []model.GameRef
+	for _, list := range lists {
+		for _, related := range list {
+			refs = append(refs, model.GameRef{IGDBID: related.ID, Name: related.Name})
+		}
+	}
+	return refs
+}
+
+// timeToBeat converts IGDB's playtime estimates, returning nil when it has none
+func timeToBeat(ttb *igdb.TimeToBeat) *model.TimeToBeat {
+	seconds := func(value *int) int {
+		if value == nil {
+			return 0
+		}
+		return *value
+	}
+
+	converted := &model.TimeToBeat{
+		Hastily:    seconds(ttb.Hastily),
+		Normally:   seconds(ttb.Normally),
+		Completely: seconds(ttb.Completely),
+		Count:      ttb.Count,
+	}
+	if converted.Hastily == 0 && converted.Normally == 0 && converted.Completely == 0 {
+		return nil
+	}
+	return converted
+}
+
+func timeToBeatEqual(a, b *model.TimeToBeat) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// platformReleases converts IGDB release dates to the game's per-platform releases
+func platformReleases(releaseDates []igdb.ReleaseDate) []model.PlatformRelease {
+	releases := make([]model.PlatformRelease, 0, len(releaseDates))
+	for _, releaseDate := range releaseDates {
+		if releaseDate.Platform == nil {
+			continue
+		}
+
+		release := model.PlatformRelease{
+			Platform: releaseDate.Platform.Abbreviation,
+			Region:   releaseDate.ReleaseRegion.String(),
+		}
+		if release.Platform == "" {
+			release.Platform = releaseDate.Platform.Name
+		}
+		if releaseDate.Date != nil {
+			date := time.Unix(*releaseDate.Date, 0).UTC()
+			release.Date = &date
+		}
+		release.Precision = datePrecision(releaseDate.DateFormat, release.Date)
+		if release.Precision != model.DatePrecisionDay {
+			release.Label = releaseDate.Human
+			if release.Label == "" {
+				release.Label = model.ReleaseLabel(release.Date, release.Precision)
+			}
+		}
+		if releaseDate.Status != nil {
+			release.Status = string(releaseDate.Status.Name)
+		}
+		releases = append(releases, release)
+	}
+
+	model.SortReleases(releases)
+	return releases
+}
+
+// datePrecision maps an IGDB date format to the model's precision
+func datePrecision(format igdb.ReleaseCategory, date *time.Time) model.DatePrecision {
+	if date == nil {
+		return model.DatePrecisionTBD
+	}
+
+	switch format {
+	case igdb.ReleaseDateFormatMonth:
+		return model.DatePrecisionMonth
+	case igdb.ReleaseDateFormatYear:
+		return model.DatePrecisionYear
+	case igdb.ReleaseDateFormatQ1, igdb.ReleaseDateFormatQ2, igdb.ReleaseDateFormatQ3, igdb.ReleaseDateFormatQ4:
+		return model.DatePrecisionQuarter
+	case igdb.ReleaseDateFormatTBD:
+		return model.DatePrecisionTBD
+	default:
+		return model.DatePrecisionDay
+	}
+}
+
+// firstReleasePrecision finds how exactly the game's first release date is known from the
+// release it came from. A game with releases but no first release date is TBD.
+func firstReleasePrecision(firstReleaseDate *int64, releases []model.PlatformRelease) (model.DatePrecision, string) {
+	if firstReleaseDate == nil {
+		if len(releases) > 0 {
+			return model.DatePrecisionTBD, "TBD"
+		}
+		return "", ""
+	}
+
+	first := time.Unix(*firstReleaseDate, 0)
+	for _, release := range releases {
+		if release.Date != nil && release.Date.Equal(first) {
+			return release.Precision, release.Label
+		}
+	}
+	return model.DatePrecisionDay, ""
+}
+
+func releasesEqual(a, b []model.PlatformRelease) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Platform != b[i].Platform || a[i].Region != b[i].Region || a[i].Status != b[i].Status ||
+			a[i].Precision != b[i].Precision || a[i].Label != b[i].Label || !timesEqual(a[i].Date, b[i].Date) {
+			return false
+		}
+	}
+	return true
+}
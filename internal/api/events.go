@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"game-tracker/internal/database"
+	"game-tracker/internal/events"
+	"game-tracker/internal/middleware"
+	"game-tracker/internal/model"
+)
+
+// Stream timings
+const (
+	eventsHeartbeat = 25 * time.Second // Keeps proxies from closing an idle stream
+	eventsRetry     = 5 * time.Second  // Reconnect delay suggested to clients
+)
+
+// GameStatusEventData is the data of a game.status_changed event
+type GameStatusEventData struct {
+	GameID     string           `json:"game_id"`
+	Status     model.GameStatus `json:"status"`
+	DatePlayed *time.Time       `json:"date_played,omitempty"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// watchRetry is how long GameWatcher waits before restarting a failed listener
+const watchRetry = 10 * time.Second
+
+// GameWatcher returns an event watcher that follows the user's games in the database, so
+// changes made by any server instance or the sync worker are published. A listener that
+// fails is restarted; since changes in between are lost, clients are told to reload.
+func GameWatcher(db *database.Client) events.Watcher {
+	return func(ctx context.Context, userID string, publish func(string, any)) {
+		for restarted := false; ; restarted = true {
+			if restarted {
+				publish(events.TypeReset, struct{}{})
+			}
+			err := db.WatchGames(ctx, userID, func(change database.GameChange) {
+				publishGameChange(publish, change)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("ERROR: %v; restarting in %s", err, watchRetry)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetry):
+			}
+		}
+	}
+}
+
+func publishGameChange(publish func(string, any), change database.GameChange) {
+	switch change.Type {
+	case database.GameCreated:
+		publish(events.TypeGameCreated, withParentRelation(change.Game))
+	case database.GameUpdated:
+		publish(events.TypeGameUpdated, withParentRelation(change.Game))
+	case database.GameStatusChanged:
+		publish(events.TypeGameStatusChanged, GameStatusEventData{
+			GameID:     change.GameID,
+			Status:     change.Status,
+			DatePlayed: change.DatePlayed,
+			UpdatedAt:  change.At,
+		})
+	case database.GameDeleted:
+		publish(events.TypeGameDeleted, map[string]string{"game_id": change.GameID})
+	}
+}
+
+// withParentRelation returns the game's list view with Relation describing its parent,
+// for clients that label DLC. Whether the parent is in the library isn't looked up.
+func withParentRelation(game *model.Game) *model.Game {
+	out := listView(game)
+	out.Relation = game.ParentRelation()
+	return out
+}
+
+// detailFields are the long text and media fields only the game's own endpoint
+// (GET /api/v1/games/{id}) serves; lists, events and webhooks leave them out, since
+// they would multiply the size of every payload
+var detailFields = map[string]bool{
+	"summary":     true,
+	"storyline":   true,
+	"screenshots": true,
+	"videos":      true,
+}
+
+// listView returns a copy of the game without its detailFields
+func listView(game *model.Game) *model.Game {
+	out := *game
+	out.Summary = ""
+	out.Storyline = ""
+	out.Screenshots = nil
+	out.Videos = nil
+	return &out
+}
+
+// listViews returns the list view of each game
+func listViews(games []*model.Game) []*model.Game {
+	out := make([]*model.Game, len(games))
+	for i, game := range games {
+		out[i] = listView(game)
+	}
+	return out
+}
+
+// handleEvents streams the user's game changes as server-sent events until the client
+// disconnects. A reconnecting client sends Last-Event-ID and gets the events it missed,
+// or a reset event when they are no longer available.
+func (h *Handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if h.broker == nil {
+		http.Error(w, "Event stream is not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	// The server's write timeout would cut the stream off
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("ERROR: Failed to clear write deadline for event stream: %v", err)
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub, replay, resumed := h.broker.Subscribe(userID, r.Header.Get("Last-Event-ID"))
+	defer h.broker.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disables nginx response buffering
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", eventsRetry.Milliseconds()); err != nil {
+		return
+	}
+	if !resumed {
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: {}\n\n", events.TypeReset); err != nil {
+			return
+		}
+	}
+	for _, event := range replay {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and resumes
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes one event in the text/event-stream format. Data is single-line JSON.
+func writeEvent(w http.ResponseWriter, event events.Event) error {
+	_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+	return err
+}
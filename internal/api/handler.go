@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -12,9 +13,15 @@ import (
 
 	"game-tracker/internal/cache"
 	"game-tracker/internal/database"
+	"game-tracker/internal/events"
+	"game-tracker/internal/export"
 	"game-tracker/internal/igdb"
+	"game-tracker/internal/importer"
+	"game-tracker/internal/matcher"
 	"game-tracker/internal/middleware"
 	"game-tracker/internal/model"
+	"game-tracker/internal/notify"
+	"game-tracker/internal/webhooks"
 )
 
 type Handler struct {
@@ -22,23 +29,60 @@ type Handler struct {
 	igdbClient *igdb.Client
 	cache      *cache.Cache
 	authClient *auth.Client
+	importer   *importer.Importer
+	matcher    *matcher.Matcher
+	syncer     Syncer
+	notifier   *notify.Service
+	dispatcher *webhooks.Dispatcher
+	broker     *events.Broker
+	adminIDs   []string
+	refreshes  *cooldown
 }
 
-func NewHandler(db *database.Client, igdbClient *igdb.Client, searchCache *cache.Cache, authClient *auth.Client) *Handler {
-	return &Handler{
+func NewHandler(db *database.Client, igdbClient *igdb.Client, searchCache *cache.Cache, authClient *auth.Client, syncer Syncer, notifier *notify.Service, dispatcher *webhooks.Dispatcher, broker *events.Broker, adminIDs []string, matchThreshold float64) *Handler {
+	h := &Handler{
 		db:         db,
 		igdbClient: igdbClient,
 		cache:      searchCache,
 		authClient: authClient,
-	}
+		syncer:     syncer,
+		notifier:   notifier,
+		dispatcher: dispatcher,
+		broker:     broker,
+		adminIDs:   adminIDs,
+		refreshes:  newCooldown(refreshCooldown),
+	}
+	h.matcher = matcher.New(db, h.searchIGDB, igdbClient.SearchAlternativeNames, matchThreshold)
+	h.importer = importer.New(db, igdbClient, h.matcher)
+	return h
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	authMW := middleware.AuthMiddleware(h.authClient)
+	adminMW := middleware.AdminMiddleware(h.adminIDs)
 
 	mux.Handle("/api/v1/games", authMW(http.HandlerFunc(h.handleGames)))
 	mux.Handle("/api/v1/games/", authMW(http.HandlerFunc(h.handleGameByID)))
 	mux.Handle("/api/v1/search", authMW(http.HandlerFunc(h.handleSearch)))
+	mux.Handle("/api/v1/export", authMW(http.HandlerFunc(h.handleExport)))
+	mux.Handle("/api/v1/import", authMW(http.HandlerFunc(h.handleImport)))
+	mux.Handle("/api/v1/import/confirm", authMW(http.HandlerFunc(h.handleImportConfirm)))
+	mux.Handle("/api/v1/calendar", authMW(http.HandlerFunc(h.handleCalendar)))
+	mux.Handle("/api/v1/recommendations", authMW(http.HandlerFunc(h.handleRecommendations)))
+	mux.Handle("/api/v1/stats", authMW(http.HandlerFunc(h.handleStats)))
+	mux.Handle("/api/v1/settings", authMW(http.HandlerFunc(h.handleSettings)))
+	mux.Handle("/api/v1/settings/calendar-token", authMW(http.HandlerFunc(h.handleCalendarToken)))
+	mux.Handle("/api/v1/notifications/test", authMW(http.HandlerFunc(h.handleNotificationTest)))
+	mux.Handle("/api/v1/notifications/deliveries", authMW(http.HandlerFunc(h.handleNotificationDeliveries)))
+	mux.Handle("/api/v1/webhooks", authMW(http.HandlerFunc(h.handleWebhooks)))
+	mux.Handle("/api/v1/webhooks/", authMW(http.HandlerFunc(h.handleWebhookByID)))
+	mux.Handle("/api/v1/events", authMW(http.HandlerFunc(h.handleEvents)))
+	// Authenticated by the secret token in the URL, for calendar clients
+	mux.Handle("/api/v1/ical/", http.HandlerFunc(h.handleCalendarFeed))
+	mux.Handle("/api/v1/backup", authMW(http.HandlerFunc(h.handleBackup)))
+	mux.Handle("/api/v1/restore", authMW(http.HandlerFunc(h.handleRestore)))
+	mux.Handle("/api/v1/admin/sync", authMW(adminMW(http.HandlerFunc(h.handleAdminSync))))
+	mux.Handle("/api/v1/admin/sync/runs", authMW(adminMW(http.HandlerFunc(h.handleAdminSyncRuns))))
 }
 
 // handleGames handles GET /api/v1/games?view={backlog|playing|history}
@@ -62,8 +106,13 @@ func (h *Handler) getGames(w http.ResponseWriter, r *http.Request) {
 
 	view := r.URL.Query().Get("view")
 
+	length, err := parseLengthQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	var games []*model.Game
-	var err error
 
 	switch view {
 	case "backlog":
@@ -73,7 +122,7 @@ func (h *Handler) getGames(w http.ResponseWriter, r *http.Request) {
 	case "history":
 		games, err = h.db.GetHistory(r.Context(), userID)
 	case "calendar":
-		games, err = h.db.GetUpcoming(r.Context(), userID)
+		games, err = h.db.GetBacklog(r.Context(), userID)
 	case "all":
 		games, err = h.db.GetAllGames(r.Context(), userID)
 	case "":
@@ -90,7 +139,37 @@ func (h *Handler) getGames(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, games)
+	// Release dates depend on the platform the user plays on
+	settings, err := h.db.GetSettings(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch settings: %v", err)
+		http.Error(w, "Failed to fetch games", http.StatusInternalServerError)
+		return
+	}
+	applyReleaseDates(games, settings.PreferredPlatform)
+
+	switch view {
+	case "backlog":
+		sortByReleaseDate(games)
+	case "calendar":
+		// Backlog games releasing from a month ago onwards
+		games = upcomingSince(games, time.Now().AddDate(0, -1, 0))
+		sortByReleaseDate(games)
+	}
+	games = length.apply(games)
+
+	// The full library is at hand for these views; the others look parents up
+	var library []*model.Game
+	if view == "all" || view == "" {
+		library = games
+	}
+	if err := h.applyRelations(r.Context(), userID, games, library); err != nil {
+		log.Printf("ERROR: Failed to look up parent games: %v", err)
+		http.Error(w, "Failed to fetch games", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, listViews(games))
 }
 
 // CreateGameRequest represents a request to create a new game
@@ -153,7 +232,7 @@ func (h *Handler) createGame(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		igdbGame, err := h.igdbClient.GetGameByID(req.IGDBID)
+		igdbGame, err := h.igdbClient.GetGameByID(r.Context(), req.IGDBID)
 		if err != nil {
 			log.Printf("ERROR: Failed to fetch IGDB metadata for game ID %d: %v", req.IGDBID, err)
 			http.Error(w, "Failed to fetch game metadata from IGDB", http.StatusInternalServerError)
@@ -175,9 +254,32 @@ func (h *Handler) createGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recordStatusChange(r, game, "")
+	EmitGameEvent(r.Context(), h.dispatcher, model.EventGameCreated, GameEventData{Game: game})
+
+	// relation.parent_in_library lets the client warn about DLC added without its base game
+	h.applyRelation(r.Context(), game)
+
 	respondJSON(w, game)
 }
 
+// recordStatusChange appends to the game's status history; failures are logged, not returned
+func (h *Handler) recordStatusChange(r *http.Request, game *model.Game, from model.GameStatus) {
+	change := &model.StatusChange{
+		UserID: game.UserID,
+		GameID: game.ID,
+		From:   from,
+		To:     game.Status,
+	}
+	if game.HasDatePlayed() {
+		change.DatePlayed = game.DatePlayed
+	}
+
+	if err := h.db.RecordStatusChange(r.Context(), change); err != nil {
+		log.Printf("ERROR: Failed to record status change for game %s: %v", game.ID, err)
+	}
+}
+
 // handleGameByID handles routes like /api/v1/games/{id}/status
 func (h *Handler) handleGameByID(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
@@ -207,6 +309,36 @@ func (h *Handler) handleGameByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check if this is a match candidates request
+	if len(parts) == 2 && parts[1] == "candidates" && r.Method == http.MethodGet {
+		h.getMatchCandidates(w, r, userID, gameID)
+		return
+	}
+
+	// Check if this is a search hint update request
+	if len(parts) == 2 && parts[1] == "search-hint" && r.Method == http.MethodPost {
+		h.updateSearchHint(w, r, userID, gameID)
+		return
+	}
+
+	// Check if this is a preferred platform update request
+	if len(parts) == 2 && parts[1] == "platform" && r.Method == http.MethodPost {
+		h.updatePreferredPlatform(w, r, userID, gameID)
+		return
+	}
+
+	// Check if this is a metadata refresh request
+	if len(parts) == 2 && parts[1] == "refresh" && r.Method == http.MethodPost {
+		h.refreshGame(w, r, userID, gameID)
+		return
+	}
+
+	// Handle GET request for the full game
+	if len(parts) == 1 && r.Method == http.MethodGet {
+		h.getGame(w, r, userID, gameID)
+		return
+	}
+
 	// Handle DELETE request for game
 	if len(parts) == 1 && r.Method == http.MethodDelete {
 		h.deleteGame(w, r, userID, gameID)
@@ -255,6 +387,8 @@ func (h *Handler) updateGameStatus(w http.ResponseWriter, r *http.Request, userI
 		return
 	}
 
+	previousStatus := game.Status
+
 	// Update status
 	if err := h.db.UpdateGameStatus(r.Context(), gameID, req.Status, req.DatePlayed); err != nil {
 		log.Printf("ERROR: Failed to update game status: %v", err)
@@ -270,12 +404,20 @@ func (h *Handler) updateGameStatus(w http.ResponseWriter, r *http.Request, userI
 		return
 	}
 
+	h.recordStatusChange(r, game, previousStatus)
+	if game.Status != previousStatus {
+		EmitGameEvent(r.Context(), h.dispatcher, model.EventGameStatusChanged, GameEventData{Game: game, PreviousStatus: previousStatus})
+	}
+
+	h.applyRelation(r.Context(), game)
 	respondJSON(w, game)
 }
 
-// UpdateMatchRequest represents a request to update game IGDB match
+// UpdateMatchRequest represents a request to update game IGDB match.
+// Either igdb_id or candidate_index (into the game's match_candidates) is set.
 type UpdateMatchRequest struct {
-	IGDBID int `json:"igdb_id"`
+	IGDBID         int  `json:"igdb_id,omitempty"`
+	CandidateIndex *int `json:"candidate_index,omitempty"`
 }
 
 // updateGameMatch handles POST /api/v1/games/{id}/match
@@ -299,6 +441,19 @@ func (h *Handler) updateGameMatch(w http.ResponseWriter, r *http.Request, userID
 		return
 	}
 
+	if req.CandidateIndex != nil {
+		if *req.CandidateIndex < 0 || *req.CandidateIndex >= len(game.MatchCandidates) {
+			http.Error(w, "Invalid candidate_index", http.StatusBadRequest)
+			return
+		}
+		req.IGDBID = game.MatchCandidates[*req.CandidateIndex].IGDBID
+	}
+
+	if req.IGDBID <= 0 {
+		http.Error(w, "igdb_id or candidate_index is required", http.StatusBadRequest)
+		return
+	}
+
 	// Check if new IGDB ID already exists for this user
 	existingGame, err := h.db.GetGameByIGDBID(r.Context(), userID, req.IGDBID)
 	if err != nil {
@@ -314,16 +469,19 @@ func (h *Handler) updateGameMatch(w http.ResponseWriter, r *http.Request, userID
 	}
 
 	// Fetch metadata from IGDB
-	igdbGame, err := h.igdbClient.GetGameByID(req.IGDBID)
+	igdbGame, err := h.igdbClient.GetGameByID(r.Context(), req.IGDBID)
 	if err != nil {
 		log.Printf("ERROR: Failed to fetch IGDB metadata for game ID %d: %v", req.IGDBID, err)
 		http.Error(w, "Failed to fetch game metadata from IGDB", http.StatusInternalServerError)
 		return
 	}
 
-	// Update game with IGDB data
+	// Update game with IGDB data; the automatic match's candidates no longer apply
+	before := *game
 	game.IGDBID = req.IGDBID
 	game.MatchStatus = model.MatchStatusMatched
+	game.MatchCandidates = nil
+	game.MatchReason = ""
 	EnrichGameFromIGDB(game, igdbGame, false)
 
 	if err := h.db.SaveGame(r.Context(), game); err != nil {
@@ -333,6 +491,36 @@ func (h *Handler) updateGameMatch(w http.ResponseWriter, r *http.Request, userID
 	}
 
 	log.Printf("Game match updated: %s (ID: %s) matched to IGDB ID: %d", game.Title, gameID, req.IGDBID)
+	EmitGameEvent(r.Context(), h.dispatcher, model.EventGameMetadataUpdated, GameEventData{Game: game, Changes: DiffGames(&before, game)})
+
+	h.applyRelation(r.Context(), game)
+	respondJSON(w, game)
+}
+
+// getGame handles GET /api/v1/games/{id}: the whole game, including the summary,
+// storyline, screenshots and videos that lists and events leave out
+func (h *Handler) getGame(w http.ResponseWriter, r *http.Request, userID, gameID string) {
+	game, err := h.db.GetGame(r.Context(), gameID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch game: %v", err)
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	if game.UserID != userID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	settings, err := h.db.GetSettings(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch settings: %v", err)
+		http.Error(w, "Failed to fetch game", http.StatusInternalServerError)
+		return
+	}
+	applyReleaseDates([]*model.Game{game}, settings.PreferredPlatform)
+
+	h.applyRelation(r.Context(), game)
 	respondJSON(w, game)
 }
 
@@ -359,6 +547,8 @@ func (h *Handler) deleteGame(w http.ResponseWriter, r *http.Request, userID, gam
 	}
 
 	log.Printf("Game deleted: %s (ID: %s)", game.Title, gameID)
+	EmitGameEvent(r.Context(), h.dispatcher, model.EventGameDeleted, GameEventData{Game: game})
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -375,26 +565,95 @@ func (h *Handler) handleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	results, err := h.searchIGDB(r.Context(), query)
+	if err != nil {
+		log.Printf("ERROR: Failed to search IGDB: %v", err)
+		http.Error(w, "Failed to search games", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, results)
+}
+
+// searchIGDB searches IGDB through the search cache
+func (h *Handler) searchIGDB(ctx context.Context, query string) ([]igdb.SearchCandidate, error) {
 	// Check cache first
 	if results, found := h.cache.Get(query); found {
 		log.Printf("Cache hit for query: %s", query)
-		respondJSON(w, results)
-		return
+		return results, nil
 	}
 
 	// Cache miss, query IGDB
 	log.Printf("Cache miss for query: %s", query)
-	results, err := h.igdbClient.Search(query)
+	results, err := h.igdbClient.Search(ctx, query)
 	if err != nil {
-		log.Printf("ERROR: Failed to search IGDB: %v", err)
-		http.Error(w, "Failed to search games", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
 	// Store in cache
 	h.cache.Set(query, results)
 
-	respondJSON(w, results)
+	return results, nil
+}
+
+// handleExport handles GET /api/v1/export?format={csv|json}
+func (h *Handler) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, "Invalid format parameter", http.StatusBadRequest)
+		return
+	}
+
+	// Large libraries can take longer than the server's write timeout to stream
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Warning: Could not extend write deadline for export: %v", err)
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="games-%s.%s"`, time.Now().Format("2006-01-02"), format))
+
+	// Headers are committed once the first row is written, so failures past this
+	// point can only be logged and the response is left truncated
+	writer, err := export.NewWriter(w, format)
+	if err != nil {
+		log.Printf("ERROR: Failed to start export: %v", err)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	count := 0
+	err = h.db.ForEachGame(r.Context(), userID, func(game *model.Game) error {
+		if err := writer.Write(game); err != nil {
+			return err
+		}
+		count++
+		if flusher != nil && count%100 == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("ERROR: Export failed for user %s after %d games: %v", userID, count, err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("ERROR: Failed to finish export for user %s: %v", userID, err)
+		return
+	}
+
+	log.Printf("Exported %d games for user %s (%s)", count, userID, format)
 }
 
 func respondJSON(w http.ResponseWriter, data interface{}) {
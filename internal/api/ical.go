@@ -0,0 +1,198 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"game-tracker/internal/ical"
+	"game-tracker/internal/middleware"
+	"game-tracker/internal/model"
+)
+
+// CalendarTokenResponse is a new iCalendar feed token and the URL to subscribe to
+type CalendarTokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// handleCalendarToken handles POST and DELETE /api/v1/settings/calendar-token. POST
+// creates the user's feed token, replacing (and so revoking) any earlier one; DELETE
+// turns the feed off.
+func (h *Handler) handleCalendarToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	settings, err := h.db.GetSettings(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch settings: %v", err)
+		http.Error(w, "Failed to fetch settings", http.StatusInternalServerError)
+		return
+	}
+
+	settings.CalendarToken = ""
+	if r.Method == http.MethodPost {
+		settings.CalendarToken = newCalendarToken()
+	}
+
+	if err := h.db.SaveSettings(r.Context(), settings); err != nil {
+		log.Printf("ERROR: Failed to save settings: %v", err)
+		http.Error(w, "Failed to save settings", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		log.Printf("Calendar feed disabled for user %s", userID)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	log.Printf("Calendar feed token created for user %s", userID)
+	respondJSON(w, CalendarTokenResponse{
+		Token: settings.CalendarToken,
+		URL:   calendarFeedURL(r, settings.CalendarToken),
+	})
+}
+
+// handleCalendarFeed handles GET /api/v1/ical/{token}.ics, the iCalendar feed of release
+// dates for the token owner's Backlog and Break games. It needs no sign-in: calendar
+// clients can't send Firebase tokens, so the secret URL is the credential.
+func (h *Handler) handleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/ical/"), ".ics")
+	if token == "" || strings.Contains(token, "/") {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	settings, err := h.db.GetSettingsByCalendarToken(r.Context(), token)
+	if err != nil {
+		log.Printf("ERROR: Failed to look up calendar token: %v", err)
+		http.Error(w, "Failed to load calendar", http.StatusInternalServerError)
+		return
+	}
+	if settings == nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	games, err := h.db.GetBacklog(r.Context(), settings.UserID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch games for calendar feed: %v", err)
+		http.Error(w, "Failed to load calendar", http.StatusInternalServerError)
+		return
+	}
+
+	applyReleaseDates(games, settings.PreferredPlatform)
+	sortByReleaseDate(games)
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="releases.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if err := ical.Write(w, ReleaseCalendar(games)); err != nil {
+		log.Printf("ERROR: Failed to write calendar feed: %v", err)
+	}
+}
+
+// ReleaseCalendar builds the feed for games with PlatformRelease filled in. Games with an
+// exact release day get an all-day event on it, games known to a month get a month-long
+// event, and games only known to a quarter, a year or not at all are left out.
+func ReleaseCalendar(games []*model.Game) *ical.Calendar {
+	cal := &ical.Calendar{
+		ProductID: "-//game-tracker//Release Calendar//EN",
+		Name:      "Game Releases",
+	}
+
+	for _, game := range games {
+		release := game.PlatformRelease
+		precision := release.DatePrecision()
+		if precision != model.DatePrecisionDay && precision != model.DatePrecisionMonth {
+			continue
+		}
+
+		start, end := model.ReleasePeriod(*release.Date, precision)
+
+		summary := game.Title
+		if release.Platform != "" {
+			summary += " (" + release.Platform + ")"
+		}
+
+		cal.Events = append(cal.Events, ical.Event{
+			UID:         game.ID + "@game-tracker",
+			Summary:     summary,
+			Description: releaseDescription(game, release),
+			URL:         firstNonEmpty(game.IGDBURL, game.SteamLink(), game.OfficialURL),
+			Start:       start,
+			End:         end,
+			Stamp:       game.UpdatedAt,
+		})
+	}
+
+	return cal
+}
+
+func releaseDescription(game *model.Game, release *model.PlatformRelease) string {
+	var lines []string
+	if release.Label != "" {
+		lines = append(lines, "Release: "+release.Label)
+	}
+	if !release.IsFullRelease() {
+		lines = append(lines, "Release status: "+release.Status)
+	}
+	if game.IGDBURL != "" {
+		lines = append(lines, "IGDB: "+game.IGDBURL)
+	}
+	if steamURL := game.SteamLink(); steamURL != "" {
+		lines = append(lines, "Steam: "+steamURL)
+	}
+	if game.OfficialURL != "" {
+		lines = append(lines, "Website: "+game.OfficialURL)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// newCalendarToken returns a random URL-safe feed token
+func newCalendarToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// calendarFeedURL is the absolute feed URL for token, as seen by the client
+func calendarFeedURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/api/v1/ical/" + token + ".ics"
+}
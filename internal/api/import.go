@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"game-tracker/internal/importer"
+	"game-tracker/internal/middleware"
+)
+
+const (
+	maxImportBodyBytes = 5 << 20
+	maxImportRows      = 1000
+)
+
+// handleImport handles POST /api/v1/import?format={csv|json|steam|backloggd|hltb|grouvee}
+// Optional title_column, status_column, date_played_column and igdb_id_column parameters
+// override the automatic column detection. Nothing is saved: the response is a preview
+// that the client sends back (possibly edited) to /api/v1/import/confirm.
+func (h *Handler) handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	mapping := importer.ColumnMapping{
+		Title:      query.Get("title_column"),
+		Status:     query.Get("status_column"),
+		DatePlayed: query.Get("date_played_column"),
+		IGDBID:     query.Get("igdb_id_column"),
+	}
+
+	format := query.Get("format")
+	if format == "" {
+		format = "csv"
+		if strings.Contains(r.Header.Get("Content-Type"), "json") {
+			format = "json"
+		}
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBodyBytes)
+
+	var rows []importer.Row
+	var err error
+
+	switch format {
+	case "csv":
+		rows, err = importer.ParseCSV(body, mapping)
+	case "json":
+		rows, err = importer.ParseJSON(body, mapping)
+	case "steam":
+		var apps []importer.SteamApp
+		apps, err = importer.ParseSteamLibrary(body)
+		if err == nil {
+			if len(apps) > maxImportRows {
+				http.Error(w, "Import file contains too many games", http.StatusRequestEntityTooLarge)
+				return
+			}
+			rows, err = h.importer.SteamRows(r.Context(), apps)
+			if err != nil {
+				log.Printf("ERROR: Failed to map Steam library to IGDB: %v", err)
+				http.Error(w, "Failed to look up Steam games on IGDB", http.StatusInternalServerError)
+				return
+			}
+		}
+	default:
+		source, ok := importer.LookupSource(format)
+		if !ok {
+			http.Error(w, "Invalid format parameter", http.StatusBadRequest)
+			return
+		}
+		rows, err = source.Parse(body)
+	}
+
+	if err != nil {
+		http.Error(w, "Failed to parse import file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.respondImportPreview(w, r, userID, rows)
+}
+
+// respondImportPreview matches parsed rows and writes the preview response
+func (h *Handler) respondImportPreview(w http.ResponseWriter, r *http.Request, userID string, rows []importer.Row) {
+	if len(rows) == 0 {
+		http.Error(w, "Import file contains no games", http.StatusBadRequest)
+		return
+	}
+	if len(rows) > maxImportRows {
+		http.Error(w, "Import file contains too many games", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Every unmatched row costs an IGDB search, which can outlast the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(10 * time.Minute)); err != nil {
+		log.Printf("Warning: Could not extend write deadline for import preview: %v", err)
+	}
+
+	preview, err := h.importer.Preview(r.Context(), userID, rows)
+	if err != nil {
+		log.Printf("ERROR: Failed to build import preview: %v", err)
+		http.Error(w, "Failed to preview import", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, preview)
+}
+
+// ConfirmImportRequest carries the rows the user accepted from a preview
+type ConfirmImportRequest struct {
+	Rows []importer.Row `json:"rows"`
+}
+
+// handleImportConfirm handles POST /api/v1/import/confirm
+func (h *Handler) handleImportConfirm(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req ConfirmImportRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxImportBodyBytes)).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Rows) == 0 {
+		http.Error(w, "No rows to import", http.StatusBadRequest)
+		return
+	}
+	if len(req.Rows) > maxImportRows {
+		http.Error(w, "Too many rows to import", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	result, err := h.importer.Commit(r.Context(), userID, req.Rows)
+	if err != nil {
+		log.Printf("ERROR: Failed to import games: %v", err)
+		http.Error(w, "Failed to import games", http.StatusInternalServerError)
+		return
+	}
+
+	EmitGameCreated(r.Context(), h.dispatcher, userID, result.Created)
+
+	result.Created = listViews(result.Created)
+	respondJSON(w, result)
+}
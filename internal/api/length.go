@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"game-tracker/internal/model"
+)
+
+// lengthQuery narrows and orders a game list by IGDB time-to-beat estimates:
+// ?min_hours=&max_hours=&pace={hastily|normally|completely}&sort=length
+type lengthQuery struct {
+	pace     model.Pace
+	minHours float64 // 0 means no minimum
+	maxHours float64 // 0 means no maximum
+	sort     bool    // Shortest first
+}
+
+func parseLengthQuery(values url.Values) (lengthQuery, error) {
+	var q lengthQuery
+
+	pace, err := model.ParsePace(values.Get("pace"))
+	if err != nil {
+		return q, fmt.Errorf("pace must be hastily, normally or completely")
+	}
+	q.pace = pace
+
+	for _, bound := range []struct {
+		name  string
+		value *float64
+	}{{"min_hours", &q.minHours}, {"max_hours", &q.maxHours}} {
+		text := values.Get(bound.name)
+		if text == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(text, 64)
+		if err != nil || parsed <= 0 {
+			return q, fmt.Errorf("%s must be a positive number", bound.name)
+		}
+		*bound.value = parsed
+	}
+	if q.minHours > 0 && q.maxHours > 0 && q.minHours > q.maxHours {
+		return q, fmt.Errorf("min_hours must not exceed max_hours")
+	}
+
+	switch values.Get("sort") {
+	case "":
+	case "length":
+		q.sort = true
+	default:
+		return q, fmt.Errorf("sort must be length")
+	}
+
+	return q, nil
+}
+
+// filters reports whether the query drops games by length
+func (q lengthQuery) filters() bool {
+	return q.minHours > 0 || q.maxHours > 0
+}
+
+// apply filters and sorts games. Filtering by length drops games without an estimate for
+// the pace; sorting puts them last, keeping the list's order otherwise.
+func (q lengthQuery) apply(games []*model.Game) []*model.Game {
+	if q.filters() {
+		kept := make([]*model.Game, 0, len(games))
+		for _, game := range games {
+			hours, ok := game.TimeToBeat.Hours(q.pace)
+			if !ok || (q.minHours > 0 && hours < q.minHours) || (q.maxHours > 0 && hours > q.maxHours) {
+				continue
+			}
+			kept = append(kept, game)
+		}
+		games = kept
+	}
+
+	if q.sort {
+		sort.SliceStable(games, func(i, j int) bool {
+			a, aOK := games[i].TimeToBeat.Hours(q.pace)
+			b, bOK := games[j].TimeToBeat.Hours(q.pace)
+			if aOK != bOK {
+				return aOK
+			}
+			return a < b
+		})
+	}
+
+	return games
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"game-tracker/internal/middleware"
+	"game-tracker/internal/model"
+)
+
+// Page sizes for GET /api/v1/notifications/deliveries
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+// normalizeNotificationSettings validates the channels of a settings update, returning a
+// message for the user when one is unusable
+func (h *Handler) normalizeNotificationSettings(settings *model.NotificationSettings) (string, bool) {
+	for i := range settings.Channels {
+		if err := h.notifier.NormalizeChannel(&settings.Channels[i]); err != nil {
+			return "Channel " + strconv.Itoa(i+1) + ": " + err.Error(), false
+		}
+	}
+	return "", true
+}
+
+// handleNotificationTest handles POST /api/v1/notifications/test: sends a test
+// notification to each enabled channel and returns how each delivery went
+func (h *Handler) handleNotificationTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	settings, err := h.db.GetSettings(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch settings: %v", err)
+		http.Error(w, "Failed to fetch settings", http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, err := h.notifier.SendTest(r.Context(), settings)
+	if err != nil {
+		log.Printf("ERROR: Failed to send test notification: %v", err)
+		http.Error(w, "Failed to send test notification", http.StatusInternalServerError)
+		return
+	}
+	if len(deliveries) == 0 {
+		http.Error(w, "No notification channels are enabled", http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, deliveries)
+}
+
+// handleNotificationDeliveries handles GET /api/v1/notifications/deliveries?limit=N: the
+// user's notification log, newest first
+func (h *Handler) handleNotificationDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit := defaultDeliveriesLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxDeliveriesLimit {
+			http.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.db.GetDeliveries(r.Context(), userID, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch notification deliveries: %v", err)
+		http.Error(w, "Failed to fetch notifications", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, deliveries)
+}
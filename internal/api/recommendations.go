@@ -0,0 +1,88 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"game-tracker/internal/middleware"
+	"game-tracker/internal/recommend"
+)
+
+const (
+	defaultRecommendationsLimit = 10
+	maxRecommendationsLimit     = 50
+)
+
+// handleRecommendations handles GET /api/v1/recommendations?mode={top|surprise}&limit=N:
+// Backlog and Break games ranked by what the user is likely to enjoy next, each with the
+// reasons behind it. Surprise mode draws games at random, favouring better scores, and
+// returns one unless limit says otherwise.
+func (h *Handler) handleRecommendations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	mode := r.URL.Query().Get("mode")
+	limit := defaultRecommendationsLimit
+	switch mode {
+	case "", "top":
+	case "surprise":
+		limit = 1
+	default:
+		http.Error(w, "mode must be top or surprise", http.StatusBadRequest)
+		return
+	}
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxRecommendationsLimit {
+			http.Error(w, "limit must be between 1 and 50", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	backlog, err := h.db.GetBacklog(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch backlog: %v", err)
+		http.Error(w, "Failed to fetch recommendations", http.StatusInternalServerError)
+		return
+	}
+
+	history, err := h.db.GetHistory(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch history: %v", err)
+		http.Error(w, "Failed to fetch recommendations", http.StatusInternalServerError)
+		return
+	}
+
+	settings, err := h.db.GetSettings(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch settings: %v", err)
+		http.Error(w, "Failed to fetch recommendations", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	applyReleaseDates(backlog, settings.PreferredPlatform)
+	recs := recommend.Rank(backlog, recommend.NewProfile(history, now), settings.PreferredPlatform, now)
+
+	if mode == "surprise" {
+		recs = recommend.Surprise(recs, limit)
+	} else {
+		recs = recs[:min(limit, len(recs))]
+	}
+	for i := range recs {
+		recs[i].Game = listView(recs[i].Game)
+	}
+
+	respondJSON(w, recs)
+}
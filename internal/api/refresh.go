@@ -0,0 +1,229 @@
+package api
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"game-tracker/internal/model"
+)
+
+// refreshCooldown limits how often one game can be refreshed on demand; IGDB data rarely
+// changes minute to minute, and the shared rate limit is better spent on the worker
+const refreshCooldown = 1 * time.Minute
+
+// FieldChange describes one metadata field changed by a refresh
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// RefreshGameResponse is the refreshed game and what changed
+type RefreshGameResponse struct {
+	Game    *model.Game   `json:"game"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// cooldown remembers when each key was last used
+type cooldown struct {
+	mu     sync.Mutex
+	period time.Duration
+	last   map[string]time.Time
+}
+
+func newCooldown(period time.Duration) *cooldown {
+	return &cooldown{period: period, last: make(map[string]time.Time)}
+}
+
+// allow records a use of key, or returns how long to wait if it was used too recently
+func (c *cooldown) allow(key string, now time.Time) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, t := range c.last {
+		if now.Sub(t) >= c.period {
+			delete(c.last, k)
+		}
+	}
+
+	if t, ok := c.last[key]; ok {
+		return c.period - now.Sub(t), false
+	}
+	c.last[key] = now
+	return 0, true
+}
+
+// release forgets the use of key recorded at now, e.g. when the work it allowed failed
+func (c *cooldown) release(key string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t, ok := c.last[key]; ok && t.Equal(now) {
+		delete(c.last, key)
+	}
+}
+
+// refreshGame handles POST /api/v1/games/{id}/refresh, re-fetching the game's IGDB
+// metadata right away through the background sync's refresh path
+func (h *Handler) refreshGame(w http.ResponseWriter, r *http.Request, userID, gameID string) {
+	game, err := h.db.GetGame(r.Context(), gameID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch game: %v", err)
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	if game.UserID != userID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if game.IGDBID == 0 {
+		http.Error(w, "Game is not matched to IGDB", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	if wait, ok := h.refreshes.allow(gameID, now); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(w, "Game was refreshed recently, try again later", http.StatusTooManyRequests)
+		return
+	}
+
+	before := *game
+	if _, err := h.syncer.RefreshGame(r.Context(), game); err != nil {
+		// A failed fetch used no metadata, so let the user retry straight away
+		h.refreshes.release(gameID, now)
+		log.Printf("ERROR: Failed to refresh game %s: %v", gameID, err)
+		http.Error(w, "Failed to fetch game metadata from IGDB", http.StatusInternalServerError)
+		return
+	}
+
+	changes := DiffGames(&before, game)
+	log.Printf("Game refreshed: %s (ID: %s), %d fields changed", game.Title, gameID, len(changes))
+
+	h.applyRelation(r.Context(), game)
+	respondJSON(w, RefreshGameResponse{Game: game, Changes: changes})
+}
+
+// DiffGames lists the IGDB-sourced fields that differ between two versions of a game
+func DiffGames(before, after *model.Game) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field string, from, to interface{}) {
+		changes = append(changes, FieldChange{Field: field, From: from, To: to})
+	}
+
+	if before.Title != after.Title {
+		add("title", before.Title, after.Title)
+	}
+	if before.CoverURL != after.CoverURL {
+		add("cover_url", before.CoverURL, after.CoverURL)
+	}
+	if before.Rating != after.Rating {
+		add("rating", before.Rating, after.Rating)
+	}
+	if !stringSlicesEqual(before.Genres, after.Genres) {
+		add("genres", before.Genres, after.Genres)
+	}
+	if !stringSlicesEqual(before.Platforms, after.Platforms) {
+		add("platforms", before.Platforms, after.Platforms)
+	}
+	if from, to := releaseDate(before), releaseDate(after); !timesEqual(from, to) {
+		add("release_date", from, to)
+	}
+	if before.ReleaseLabel != after.ReleaseLabel {
+		add("release_label", before.ReleaseLabel, after.ReleaseLabel)
+	}
+	if !releasesEqual(before.Releases, after.Releases) {
+		add("releases", before.Releases, after.Releases)
+	}
+	if before.IGDBURL != after.IGDBURL {
+		add("igdb_url", before.IGDBURL, after.IGDBURL)
+	}
+	if before.SteamURL != after.SteamURL {
+		add("steam_url", before.SteamURL, after.SteamURL)
+	}
+	if before.OfficialURL != after.OfficialURL {
+		add("official_url", before.OfficialURL, after.OfficialURL)
+	}
+	if !timeToBeatEqual(before.TimeToBeat, after.TimeToBeat) {
+		add("time_to_beat", before.TimeToBeat, after.TimeToBeat)
+	}
+	if before.GameType != after.GameType {
+		add("game_type", before.GameType, after.GameType)
+	}
+	if !gameRefEqual(before.ParentGame, after.ParentGame) {
+		add("parent_game", before.ParentGame, after.ParentGame)
+	}
+	if !stringSlicesEqual(before.Franchises, after.Franchises) {
+		add("franchises", before.Franchises, after.Franchises)
+	}
+	if !stringSlicesEqual(before.Collections, after.Collections) {
+		add("collections", before.Collections, after.Collections)
+	}
+	if !slices.Equal(before.DLCs, after.DLCs) {
+		add("dlcs", before.DLCs, after.DLCs)
+	}
+	if !slices.Equal(before.Expansions, after.Expansions) {
+		add("expansions", before.Expansions, after.Expansions)
+	}
+	if !slices.Equal(before.Remasters, after.Remasters) {
+		add("remasters", before.Remasters, after.Remasters)
+	}
+	if !stringSlicesEqual(before.Developers, after.Developers) {
+		add("developers", before.Developers, after.Developers)
+	}
+	if !stringSlicesEqual(before.Publishers, after.Publishers) {
+		add("publishers", before.Publishers, after.Publishers)
+	}
+	if before.Summary != after.Summary {
+		add("summary", before.Summary, after.Summary)
+	}
+	if before.Storyline != after.Storyline {
+		add("storyline", before.Storyline, after.Storyline)
+	}
+	if !stringSlicesEqual(before.Themes, after.Themes) {
+		add("themes", before.Themes, after.Themes)
+	}
+	if !stringSlicesEqual(before.GameModes, after.GameModes) {
+		add("game_modes", before.GameModes, after.GameModes)
+	}
+	if !stringSlicesEqual(before.PlayerPerspectives, after.PlayerPerspectives) {
+		add("player_perspectives", before.PlayerPerspectives, after.PlayerPerspectives)
+	}
+	if !stringSlicesEqual(before.Screenshots, after.Screenshots) {
+		add("screenshots", before.Screenshots, after.Screenshots)
+	}
+	if !slices.Equal(before.Videos, after.Videos) {
+		add("videos", before.Videos, after.Videos)
+	}
+
+	return changes
+}
+
+// releaseDate returns the game's release date, or nil for the database sentinel
+func releaseDate(game *model.Game) *time.Time {
+	if !game.HasReleaseDate() {
+		return nil
+	}
+	return game.ReleaseDate
+}
+
+func gameRefEqual(a, b *model.GameRef) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func timesEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"log"
+
+	"game-tracker/internal/model"
+)
+
+// applyRelations fills in each game's Relation, looking its parent game up in the user's
+// library. library holds games already at hand (it may be nil); parents not among them
+// are fetched from the database.
+func (h *Handler) applyRelations(ctx context.Context, userID string, games, library []*model.Game) error {
+	known := make(map[int]*model.Game, len(library)+len(games))
+	for _, list := range [][]*model.Game{library, games} {
+		for _, game := range list {
+			if game.IGDBID != 0 {
+				known[game.IGDBID] = game
+			}
+		}
+	}
+
+	var missing []int
+	for _, game := range games {
+		game.Relation = game.ParentRelation()
+		if game.Relation == nil {
+			continue
+		}
+		if _, ok := known[game.Relation.Parent.IGDBID]; !ok {
+			missing = append(missing, game.Relation.Parent.IGDBID)
+			known[game.Relation.Parent.IGDBID] = nil // Look each parent up once
+		}
+	}
+
+	if len(missing) > 0 {
+		found, err := h.db.GetGamesByIGDBIDs(ctx, userID, missing)
+		if err != nil {
+			return err
+		}
+		for igdbID, game := range found {
+			known[igdbID] = game
+		}
+	}
+
+	for _, game := range games {
+		if game.Relation == nil {
+			continue
+		}
+		if parent := known[game.Relation.Parent.IGDBID]; parent != nil {
+			game.Relation.ParentGameID = parent.ID
+			game.Relation.ParentInLibrary = true
+		}
+	}
+
+	return nil
+}
+
+// applyRelation fills in a single game's Relation for a response. A failed lookup only
+// leaves the parent unresolved, so it is logged rather than failing the request.
+func (h *Handler) applyRelation(ctx context.Context, game *model.Game) {
+	if err := h.applyRelations(ctx, game.UserID, []*model.Game{game}, nil); err != nil {
+		log.Printf("ERROR: Failed to look up parent game of %s: %v", game.ID, err)
+	}
+}
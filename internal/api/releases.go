@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"game-tracker/internal/model"
+)
+
+// UpdatePlatformRequest sets the platform the user plans to play a game on
+type UpdatePlatformRequest struct {
+	Platform string `json:"platform"` // Platform abbreviation from the game's platforms; empty uses the settings default
+}
+
+// updatePreferredPlatform handles POST /api/v1/games/{id}/platform
+func (h *Handler) updatePreferredPlatform(w http.ResponseWriter, r *http.Request, userID, gameID string) {
+	var req UpdatePlatformRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.db.GetGame(r.Context(), gameID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch game: %v", err)
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	if game.UserID != userID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	platform := strings.TrimSpace(req.Platform)
+	if platform != "" && len(game.Platforms) > 0 && !slices.Contains(game.Platforms, platform) {
+		http.Error(w, "Game is not released on that platform", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.db.UpdatePreferredPlatform(r.Context(), gameID, platform); err != nil {
+		log.Printf("ERROR: Failed to update preferred platform: %v", err)
+		http.Error(w, "Failed to update preferred platform", http.StatusInternalServerError)
+		return
+	}
+
+	game.PreferredPlatform = platform
+	if settings, err := h.db.GetSettings(r.Context(), userID); err == nil {
+		applyReleaseDates([]*model.Game{game}, settings.PreferredPlatform)
+	}
+
+	log.Printf("Preferred platform updated: %s (ID: %s) -> %q", game.Title, gameID, platform)
+	h.applyRelation(r.Context(), game)
+	respondJSON(w, game)
+}
+
+// applyReleaseDates fills in each game's PlatformRelease for the user's default platform
+func applyReleaseDates(games []*model.Game, defaultPlatform string) {
+	for _, game := range games {
+		release := game.ReleaseFor(defaultPlatform)
+		game.PlatformRelease = &release
+	}
+}
+
+// sortByReleaseDate orders games by PlatformRelease, earliest first. Imprecise dates sort
+// at the end of their month, quarter or year, and undated games last.
+func sortByReleaseDate(games []*model.Game) {
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].PlatformRelease.Before(games[j].PlatformRelease)
+	})
+}
+
+// upcomingSince keeps games that may still release on or after since: imprecise dates
+// count until their period ends, and undated games are kept (shown as TBD)
+func upcomingSince(games []*model.Game, since time.Time) []*model.Game {
+	upcoming := make([]*model.Game, 0, len(games))
+	for _, game := range games {
+		if !game.PlatformRelease.PeriodEnd().Before(since) {
+			upcoming = append(upcoming, game)
+		}
+	}
+	return upcoming
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"game-tracker/internal/middleware"
+	"game-tracker/internal/model"
+)
+
+// UpdateSettingsRequest replaces the user's editable settings. Notifications are left
+// as they are when omitted.
+type UpdateSettingsRequest struct {
+	PreferredPlatform string                      `json:"preferred_platform"`
+	Notifications     *model.NotificationSettings `json:"notifications,omitempty"`
+}
+
+// handleSettings handles GET and PUT /api/v1/settings
+func (h *Handler) handleSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		settings, err := h.db.GetSettings(r.Context(), userID)
+		if err != nil {
+			log.Printf("ERROR: Failed to fetch settings: %v", err)
+			http.Error(w, "Failed to fetch settings", http.StatusInternalServerError)
+			return
+		}
+		respondJSON(w, settings)
+	case http.MethodPut:
+		var req UpdateSettingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Notifications != nil {
+			if message, ok := h.normalizeNotificationSettings(req.Notifications); !ok {
+				http.Error(w, message, http.StatusBadRequest)
+				return
+			}
+		}
+
+		settings, err := h.db.GetSettings(r.Context(), userID)
+		if err != nil {
+			log.Printf("ERROR: Failed to fetch settings: %v", err)
+			http.Error(w, "Failed to fetch settings", http.StatusInternalServerError)
+			return
+		}
+
+		settings.PreferredPlatform = strings.TrimSpace(req.PreferredPlatform)
+		if req.Notifications != nil {
+			settings.Notifications = *req.Notifications
+		}
+		if err := h.db.SaveSettings(r.Context(), settings); err != nil {
+			log.Printf("ERROR: Failed to save settings: %v", err)
+			http.Error(w, "Failed to save settings", http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Settings updated for user %s", userID)
+		respondJSON(w, settings)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"log"
+	"math"
+	"net/http"
+
+	"game-tracker/internal/middleware"
+	"game-tracker/internal/model"
+)
+
+// Stats summarizes the user's library
+type Stats struct {
+	Total    int                      `json:"total"`
+	ByStatus map[model.GameStatus]int `json:"by_status"`
+	Backlog  BacklogStats             `json:"backlog"`
+}
+
+// BacklogStats estimates how long the Backlog and Break games would take to play through,
+// from IGDB time-to-beat data. Each total only covers games with an estimate for that
+// pace; Estimated counts games with the normal estimate.
+type BacklogStats struct {
+	Games           int     `json:"games"`
+	Estimated       int     `json:"estimated"`
+	Hours           float64 `json:"hours"` // Main story and some extras
+	HastilyHours    float64 `json:"hastily_hours"`
+	CompletelyHours float64 `json:"completely_hours"`
+}
+
+// handleStats handles GET /api/v1/stats
+func (h *Handler) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	games, err := h.db.GetGames(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch games: %v", err)
+		http.Error(w, "Failed to fetch stats", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, LibraryStats(games))
+}
+
+// LibraryStats computes the stats for a user's games
+func LibraryStats(games []*model.Game) Stats {
+	stats := Stats{
+		Total:    len(games),
+		ByStatus: make(map[model.GameStatus]int),
+	}
+
+	for _, game := range games {
+		stats.ByStatus[game.Status]++
+		if game.Status != model.StatusBacklog && game.Status != model.StatusBreak {
+			continue
+		}
+
+		stats.Backlog.Games++
+		if hours, ok := game.TimeToBeat.Hours(model.PaceNormally); ok {
+			stats.Backlog.Estimated++
+			stats.Backlog.Hours += hours
+		}
+		if hours, ok := game.TimeToBeat.Hours(model.PaceHastily); ok {
+			stats.Backlog.HastilyHours += hours
+		}
+		if hours, ok := game.TimeToBeat.Hours(model.PaceCompletely); ok {
+			stats.Backlog.CompletelyHours += hours
+		}
+	}
+
+	stats.Backlog.Hours = roundHours(stats.Backlog.Hours)
+	stats.Backlog.HastilyHours = roundHours(stats.Backlog.HastilyHours)
+	stats.Backlog.CompletelyHours = roundHours(stats.Backlog.CompletelyHours)
+	return stats
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*10) / 10
+}
@@ -0,0 +1,348 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"game-tracker/internal/middleware"
+	"game-tracker/internal/model"
+	"game-tracker/internal/netguard"
+	"game-tracker/internal/webhooks"
+)
+
+// maxWebhookSubscriptions caps each user's subscriptions, since every game change fans
+// out to all of them
+const maxWebhookSubscriptions = 10
+
+// Page sizes for GET /api/v1/webhooks/{id}/deliveries
+const (
+	defaultWebhookDeliveriesLimit = 50
+	maxWebhookDeliveriesLimit     = 200
+)
+
+// GameEventData is the data of game.* webhook events
+type GameEventData struct {
+	Game           *model.Game      `json:"game"`
+	PreviousStatus model.GameStatus `json:"previous_status,omitempty"` // game.status_changed
+	Changes        []FieldChange    `json:"changes,omitempty"`         // game.metadata_updated
+}
+
+// eventView leaves the detailFields out of the payload: the game gets its list view, and
+// changes to those fields are listed without their values
+func (d GameEventData) eventView() GameEventData {
+	d.Game = listView(d.Game)
+	if len(d.Changes) > 0 {
+		changes := make([]FieldChange, len(d.Changes))
+		for i, change := range d.Changes {
+			if detailFields[change.Field] {
+				change.From, change.To = nil, nil
+			}
+			changes[i] = change
+		}
+		d.Changes = changes
+	}
+	return d
+}
+
+// EmitGameEvent sends a game event to the owner's webhook subscriptions. Failures are
+// logged, not returned: webhooks never fail the change that triggered them.
+func EmitGameEvent(ctx context.Context, dispatcher *webhooks.Dispatcher, event model.WebhookEvent, data GameEventData) {
+	if dispatcher == nil {
+		return
+	}
+	if err := dispatcher.Emit(ctx, data.Game.UserID, event, data.eventView()); err != nil {
+		log.Printf("ERROR: Failed to emit %s webhook for game %s: %v", event, data.Game.ID, err)
+	}
+}
+
+// EmitGameCreated sends a game.created event per game, looking up the user's
+// subscriptions once, for bulk imports
+func EmitGameCreated(ctx context.Context, dispatcher *webhooks.Dispatcher, userID string, games []*model.Game) {
+	if dispatcher == nil || len(games) == 0 {
+		return
+	}
+	data := make([]any, len(games))
+	for i, game := range games {
+		data[i] = GameEventData{Game: game}.eventView()
+	}
+	if err := dispatcher.EmitEach(ctx, userID, model.EventGameCreated, data); err != nil {
+		log.Printf("ERROR: Failed to emit %s webhooks for user %s: %v", model.EventGameCreated, userID, err)
+	}
+}
+
+// WebhookRequest creates or updates a subscription
+type WebhookRequest struct {
+	URL         string               `json:"url"`
+	Description string               `json:"description"`
+	Events      []model.WebhookEvent `json:"events"`           // Event types, or "*" for all
+	Active      *bool                `json:"active,omitempty"` // Defaults to true on create; left as is on update when omitted
+}
+
+// WebhookSecretResponse is a subscription with its signing secret, returned only when
+// the secret is created or rotated
+type WebhookSecretResponse struct {
+	*model.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// handleWebhooks handles GET and POST /api/v1/webhooks
+func (h *Handler) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	subs, err := h.db.GetWebhookSubscriptions(r.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch webhook subscriptions: %v", err)
+		http.Error(w, "Failed to fetch webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		respondJSON(w, subs)
+	case http.MethodPost:
+		if len(subs) >= maxWebhookSubscriptions {
+			http.Error(w, "Too many webhooks; delete one first", http.StatusBadRequest)
+			return
+		}
+
+		var req WebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		sub := &model.WebhookSubscription{
+			UserID: userID,
+			Secret: webhooks.NewSecret(),
+			Active: true,
+		}
+		if message, ok := applyWebhookRequest(sub, &req, h.dispatcher); !ok {
+			http.Error(w, message, http.StatusBadRequest)
+			return
+		}
+
+		if err := h.db.SaveWebhookSubscription(r.Context(), sub); err != nil {
+			log.Printf("ERROR: Failed to save webhook subscription: %v", err)
+			http.Error(w, "Failed to save webhook", http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Webhook %s created for user %s", sub.ID, userID)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(WebhookSecretResponse{WebhookSubscription: sub, Secret: sub.Secret}); err != nil {
+			log.Printf("ERROR: Failed to encode JSON response: %v", err)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleWebhookByID handles routes under /api/v1/webhooks/{id}
+func (h *Handler) handleWebhookByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/webhooks/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		http.Error(w, "Webhook ID required", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.db.GetWebhookSubscription(r.Context(), parts[0])
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch webhook subscription: %v", err)
+		http.Error(w, "Failed to fetch webhook", http.StatusInternalServerError)
+		return
+	}
+	if sub == nil {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+	if sub.UserID != userID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		respondJSON(w, sub)
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		h.updateWebhook(w, r, sub)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		h.deleteWebhook(w, r, sub)
+	case len(parts) == 2 && parts[1] == "secret" && r.Method == http.MethodPost:
+		h.rotateWebhookSecret(w, r, sub)
+	case len(parts) == 2 && parts[1] == "ping" && r.Method == http.MethodPost:
+		h.pingWebhook(w, r, sub)
+	case len(parts) == 2 && parts[1] == "deliveries" && r.Method == http.MethodGet:
+		h.getWebhookDeliveries(w, r, sub)
+	case len(parts) == 4 && parts[1] == "deliveries" && parts[3] == "redeliver" && r.Method == http.MethodPost:
+		h.redeliverWebhook(w, r, sub, parts[2])
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// updateWebhook handles PUT /api/v1/webhooks/{id}
+func (h *Handler) updateWebhook(w http.ResponseWriter, r *http.Request, sub *model.WebhookSubscription) {
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if message, ok := applyWebhookRequest(sub, &req, h.dispatcher); !ok {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.db.SaveWebhookSubscription(r.Context(), sub); err != nil {
+		log.Printf("ERROR: Failed to save webhook subscription: %v", err)
+		http.Error(w, "Failed to save webhook", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Webhook %s updated", sub.ID)
+	respondJSON(w, sub)
+}
+
+// deleteWebhook handles DELETE /api/v1/webhooks/{id}
+func (h *Handler) deleteWebhook(w http.ResponseWriter, r *http.Request, sub *model.WebhookSubscription) {
+	if err := h.db.DeleteWebhookSubscription(r.Context(), sub.ID); err != nil {
+		log.Printf("ERROR: Failed to delete webhook subscription: %v", err)
+		http.Error(w, "Failed to delete webhook", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Webhook %s deleted", sub.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// rotateWebhookSecret handles POST /api/v1/webhooks/{id}/secret: replaces the signing
+// secret. Retries of earlier deliveries are signed with the new one.
+func (h *Handler) rotateWebhookSecret(w http.ResponseWriter, r *http.Request, sub *model.WebhookSubscription) {
+	sub.Secret = webhooks.NewSecret()
+	if err := h.db.SaveWebhookSubscription(r.Context(), sub); err != nil {
+		log.Printf("ERROR: Failed to save webhook subscription: %v", err)
+		http.Error(w, "Failed to rotate secret", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Webhook %s secret rotated", sub.ID)
+	respondJSON(w, WebhookSecretResponse{WebhookSubscription: sub, Secret: sub.Secret})
+}
+
+// pingWebhook handles POST /api/v1/webhooks/{id}/ping: queues a ping event and returns
+// its delivery, whose outcome shows up in the delivery log
+func (h *Handler) pingWebhook(w http.ResponseWriter, r *http.Request, sub *model.WebhookSubscription) {
+	delivery, err := h.dispatcher.Ping(r.Context(), sub)
+	if err != nil {
+		log.Printf("ERROR: Failed to queue webhook ping: %v", err)
+		http.Error(w, "Failed to send ping", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(delivery); err != nil {
+		log.Printf("ERROR: Failed to encode JSON response: %v", err)
+	}
+}
+
+// getWebhookDeliveries handles GET /api/v1/webhooks/{id}/deliveries?limit=N, newest first
+func (h *Handler) getWebhookDeliveries(w http.ResponseWriter, r *http.Request, sub *model.WebhookSubscription) {
+	limit := defaultWebhookDeliveriesLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxWebhookDeliveriesLimit {
+			http.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.db.GetWebhookDeliveries(r.Context(), sub.ID, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch webhook deliveries: %v", err)
+		http.Error(w, "Failed to fetch deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, deliveries)
+}
+
+// redeliverWebhook handles POST /api/v1/webhooks/{id}/deliveries/{deliveryID}/redeliver:
+// queues the delivery's event again as a new delivery
+func (h *Handler) redeliverWebhook(w http.ResponseWriter, r *http.Request, sub *model.WebhookSubscription, deliveryID string) {
+	delivery, err := h.db.GetWebhookDelivery(r.Context(), deliveryID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch webhook delivery: %v", err)
+		http.Error(w, "Failed to fetch delivery", http.StatusInternalServerError)
+		return
+	}
+	if delivery == nil || delivery.SubscriptionID != sub.ID {
+		http.Error(w, "Delivery not found", http.StatusNotFound)
+		return
+	}
+
+	redelivery, err := h.dispatcher.Redeliver(r.Context(), sub, delivery)
+	if err != nil {
+		log.Printf("ERROR: Failed to queue webhook redelivery: %v", err)
+		http.Error(w, "Failed to redeliver", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(redelivery); err != nil {
+		log.Printf("ERROR: Failed to encode JSON response: %v", err)
+	}
+}
+
+// applyWebhookRequest validates a request and copies it onto the subscription,
+// returning a message for the user when it is invalid
+func applyWebhookRequest(sub *model.WebhookSubscription, req *WebhookRequest, dispatcher *webhooks.Dispatcher) (string, bool) {
+	target := strings.TrimSpace(req.URL)
+	if err := dispatcher.CheckURL(target); err != nil {
+		if errors.Is(err, netguard.ErrBlocked) {
+			return "url can't point at a private or local address", false
+		}
+		return "url must be an http(s) URL", false
+	}
+
+	if len(req.Events) == 0 {
+		return "events is required (use \"*\" for all events)", false
+	}
+	var events []model.WebhookEvent
+	seen := make(map[model.WebhookEvent]bool)
+	for _, event := range req.Events {
+		if !event.IsValid() {
+			return "Unknown event: " + string(event), false
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	sub.URL = target
+	sub.Description = strings.TrimSpace(req.Description)
+	sub.Events = events
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	return "", true
+}
@@ -0,0 +1,418 @@
+// Package backup writes and restores lossless per-user archives.
+//
+// An archive is JSON lines. The first line is a header naming the format and version,
+// followed by one record per line and a trailer with record counts:
+//
+//	{"type":"header","data":{"format":"game-tracker-backup","version":1,"user_id":"...","created_at":"..."}}
+//	{"type":"settings","data":{...}}
+//	{"type":"game","data":{...}}
+//	{"type":"status_change","data":{...}}
+//	{"type":"trailer","data":{"games":120,"status_changes":340,"settings":1}}
+//
+// Records are the JSON form of the model types with database sentinel dates removed,
+// so archives can be restored into any storage backend implementing Sink.
+// Archives may be gzip-compressed; Restore detects compression automatically.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"game-tracker/internal/model"
+)
+
+const (
+	FormatName = "game-tracker-backup"
+	// Version is bumped whenever a record changes incompatibly; Restore upgrades older versions
+	Version = 1
+)
+
+const (
+	recordHeader       = "header"
+	recordSettings     = "settings"
+	recordGame         = "game"
+	recordStatusChange = "status_change"
+	recordTrailer      = "trailer"
+)
+
+// maxLineBytes bounds a single archive line; games are a few KB at most
+const maxLineBytes = 4 << 20
+
+// ErrTooLarge is returned when an archive exceeds RestoreOptions.MaxBytes or MaxRecords
+var ErrTooLarge = errors.New("archive is too large")
+
+// Source is the storage an archive is read from (database.Client implements it)
+type Source interface {
+	GetSettings(ctx context.Context, userID string) (*model.UserSettings, error)
+	ForEachGame(ctx context.Context, userID string, fn func(*model.Game) error) error
+	ForEachStatusChange(ctx context.Context, userID string, fn func(*model.StatusChange) error) error
+}
+
+// Sink is the storage an archive is restored into (database.Client implements it).
+// Records must be written as given, without touching timestamps. Archive IDs and the
+// header's user come from the file, so a sink must refuse to overwrite a game or status
+// change that belongs to a different user.
+type Sink interface {
+	RestoreSettings(ctx context.Context, settings *model.UserSettings) error
+	RestoreGame(ctx context.Context, game *model.Game) error
+	RestoreStatusChange(ctx context.Context, change *model.StatusChange) error
+}
+
+type Header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Summary counts the records written to or restored from an archive
+type Summary struct {
+	Games         int `json:"games"`
+	StatusChanges int `json:"status_changes"`
+	Settings      int `json:"settings"`
+}
+
+type record struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type encoder struct {
+	enc *json.Encoder
+}
+
+func (e *encoder) write(recordType string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", recordType, err)
+	}
+	if err := e.enc.Encode(record{Type: recordType, Data: raw}); err != nil {
+		return fmt.Errorf("failed to write %s record: %w", recordType, err)
+	}
+	return nil
+}
+
+// Write streams a full archive of the user's data to w
+func Write(ctx context.Context, w io.Writer, src Source, userID string) (*Summary, error) {
+	e := &encoder{enc: json.NewEncoder(w)}
+	summary := &Summary{}
+
+	header := Header{
+		Format:    FormatName,
+		Version:   Version,
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := e.write(recordHeader, header); err != nil {
+		return nil, err
+	}
+
+	settings, err := src.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	withoutCredentials(settings)
+	if err := e.write(recordSettings, settings); err != nil {
+		return nil, err
+	}
+	summary.Settings++
+
+	err = src.ForEachGame(ctx, userID, func(game *model.Game) error {
+		if err := e.write(recordGame, withoutSentinels(game)); err != nil {
+			return err
+		}
+		summary.Games++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = src.ForEachStatusChange(ctx, userID, func(change *model.StatusChange) error {
+		if err := e.write(recordStatusChange, change); err != nil {
+			return err
+		}
+		summary.StatusChanges++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.write(recordTrailer, summary); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// RestoreOptions controls where an archive is restored
+type RestoreOptions struct {
+	// TargetUserID owns the restored records; empty keeps the archive's user
+	TargetUserID string
+	// NewIDs gives every game and history entry a fresh ID. It is forced on when restoring
+	// into a different user so the original user's documents are never overwritten.
+	NewIDs bool
+	// MaxBytes caps the archive's size after decompression; zero means no limit
+	MaxBytes int64
+	// MaxRecords caps the number of records held in memory for validation; zero means no limit
+	MaxRecords int
+}
+
+// Restore reads an archive and writes its records to sink. The archive is validated
+// completely (header, record types, trailer counts) before anything is written, so a
+// truncated or corrupt file is rejected without a partial restore. Validation failures
+// return a nil Summary; write failures return the counts restored so far.
+func Restore(ctx context.Context, r io.Reader, sink Sink, opts RestoreOptions) (*Summary, error) {
+	archive, err := read(r, opts.MaxBytes, opts.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	targetUserID := opts.TargetUserID
+	if targetUserID == "" {
+		targetUserID = archive.header.UserID
+	}
+	newIDs := opts.NewIDs || targetUserID != archive.header.UserID
+
+	gameIDs := make(map[string]string, len(archive.games))
+	for _, game := range archive.games {
+		newID := game.ID
+		if newIDs {
+			newID = generateID()
+		}
+		gameIDs[game.ID] = newID
+	}
+
+	summary := &Summary{}
+
+	if archive.settings != nil {
+		// Never bring back credentials, even from older archives that have them: they may
+		// have been revoked since
+		withoutCredentials(archive.settings)
+		archive.settings.UserID = targetUserID
+		if err := sink.RestoreSettings(ctx, archive.settings); err != nil {
+			return summary, err
+		}
+		summary.Settings++
+	}
+
+	for _, game := range archive.games {
+		game.ID = gameIDs[game.ID]
+		game.UserID = targetUserID
+		if err := sink.RestoreGame(ctx, game); err != nil {
+			return summary, fmt.Errorf("failed to restore game '%s': %w", game.Title, err)
+		}
+		summary.Games++
+	}
+
+	for _, change := range archive.statusChanges {
+		if newID, ok := gameIDs[change.GameID]; ok {
+			change.GameID = newID
+		}
+		if newIDs {
+			change.ID = generateID()
+		}
+		change.UserID = targetUserID
+		if err := sink.RestoreStatusChange(ctx, change); err != nil {
+			return summary, err
+		}
+		summary.StatusChanges++
+	}
+
+	return summary, nil
+}
+
+type archive struct {
+	header        Header
+	settings      *model.UserSettings
+	games         []*model.Game
+	statusChanges []*model.StatusChange
+}
+
+func read(r io.Reader, maxBytes int64, maxRecords int) (*archive, error) {
+	reader, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	// Limit the decompressed stream, not just the upload, so a small gzip bomb can't
+	// expand without bound
+	if maxBytes > 0 {
+		reader = &limitedReader{r: reader, limit: maxBytes}
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+
+	a := &archive{}
+	var trailer *Summary
+	line := 0
+	records := 0
+
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		records++
+		if maxRecords > 0 && records > maxRecords {
+			return nil, fmt.Errorf("%w: more than %d records", ErrTooLarge, maxRecords)
+		}
+
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("invalid record on line %d: %w", line, err)
+		}
+
+		if line == 1 {
+			if rec.Type != recordHeader {
+				return nil, fmt.Errorf("not a %s archive: missing header", FormatName)
+			}
+			if err := json.Unmarshal(rec.Data, &a.header); err != nil {
+				return nil, fmt.Errorf("invalid header: %w", err)
+			}
+			if a.header.Format != FormatName {
+				return nil, fmt.Errorf("not a %s archive: format %q", FormatName, a.header.Format)
+			}
+			if a.header.Version < 1 || a.header.Version > Version {
+				return nil, fmt.Errorf("unsupported archive version %d (supported: 1-%d)", a.header.Version, Version)
+			}
+			continue
+		}
+
+		if trailer != nil {
+			return nil, fmt.Errorf("unexpected record after trailer on line %d", line)
+		}
+
+		switch rec.Type {
+		case recordSettings:
+			var settings model.UserSettings
+			if err := json.Unmarshal(rec.Data, &settings); err != nil {
+				return nil, fmt.Errorf("invalid settings on line %d: %w", line, err)
+			}
+			a.settings = &settings
+		case recordGame:
+			var game model.Game
+			if err := json.Unmarshal(rec.Data, &game); err != nil {
+				return nil, fmt.Errorf("invalid game on line %d: %w", line, err)
+			}
+			if game.ID == "" {
+				return nil, fmt.Errorf("game without ID on line %d", line)
+			}
+			a.games = append(a.games, &game)
+		case recordStatusChange:
+			var change model.StatusChange
+			if err := json.Unmarshal(rec.Data, &change); err != nil {
+				return nil, fmt.Errorf("invalid status change on line %d: %w", line, err)
+			}
+			a.statusChanges = append(a.statusChanges, &change)
+		case recordTrailer:
+			trailer = &Summary{}
+			if err := json.Unmarshal(rec.Data, trailer); err != nil {
+				return nil, fmt.Errorf("invalid trailer on line %d: %w", line, err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown record type %q on line %d", rec.Type, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	if line == 0 {
+		return nil, fmt.Errorf("archive is empty")
+	}
+	if trailer == nil {
+		return nil, fmt.Errorf("archive is truncated: missing trailer")
+	}
+	if trailer.Games != len(a.games) || trailer.StatusChanges != len(a.statusChanges) {
+		return nil, fmt.Errorf("archive is incomplete: trailer lists %d games and %d status changes, found %d and %d",
+			trailer.Games, trailer.StatusChanges, len(a.games), len(a.statusChanges))
+	}
+
+	return a, nil
+}
+
+// decompress transparently unwraps gzip-compressed archives
+func decompress(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip archive: %w", err)
+		}
+		return gz, nil
+	}
+	return buffered, nil
+}
+
+// limitedReader reads at most limit bytes from r, then fails with ErrTooLarge instead
+// of reporting EOF like io.LimitReader, so an oversized archive can't pass as a
+// truncated one
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	read  int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	// Read one byte past the limit to tell an archive of exactly the limit from a larger one
+	if remaining := l.limit - l.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		return 0, fmt.Errorf("%w: more than %d bytes uncompressed", ErrTooLarge, l.limit)
+	}
+	return n, err
+}
+
+// withoutCredentials clears the live credentials in settings, since archives get passed
+// around and outlive revocations: the calendar feed token and notification channel
+// tokens. Channels that had a token (and Gotify channels, which always need one) are
+// disabled until the user enters a new one.
+func withoutCredentials(settings *model.UserSettings) {
+	settings.CalendarToken = ""
+	for i := range settings.Notifications.Channels {
+		channel := &settings.Notifications.Channels[i]
+		if channel.Token != "" || channel.Type == model.ChannelGotify {
+			channel.Token = ""
+			channel.Enabled = false
+		}
+	}
+}
+
+// withoutSentinels returns a copy of the game with database sentinel dates cleared
+func withoutSentinels(game *model.Game) *model.Game {
+	out := *game
+	if !game.HasReleaseDate() {
+		out.ReleaseDate = nil
+	}
+	if !game.HasDatePlayed() {
+		out.DatePlayed = nil
+	}
+	return &out
+}
+
+const idAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// generateID returns a random 20 character ID in the same shape as Firestore's auto IDs
+func generateID() string {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	for i := range b {
+		b[i] = idAlphabet[int(b[i])%len(idAlphabet)]
+	}
+	return string(b)
+}
@@ -2,9 +2,15 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"game-tracker/internal/netguard"
 )
 
 type Config struct {
@@ -22,6 +28,38 @@ type Config struct {
 		Host   string
 		NoSync bool
 	}
+	Admin struct {
+		UserIDs []string // Firebase UIDs allowed to use the admin API
+	}
+	Match struct {
+		Threshold float64 // Confidence (0-1) needed to match a game automatically
+	}
+	Sync struct {
+		Interval    time.Duration // Time between sync runs
+		Concurrency int           // Games synced in parallel
+		GameTimeout time.Duration // Upper bound for syncing or matching a single game
+		MaxFailures int           // Consecutive failures before a game's sync is disabled
+		LeaseTTL    time.Duration // Leader lease lifetime; zero disables leader election
+	}
+	SMTP struct {
+		Host     string // Empty disables email notifications
+		Port     int
+		Username string
+		Password string
+		From     string
+	}
+	Notify struct {
+		MaxAttempts int           // Delivery attempts before a notification is given up on
+		Timeout     time.Duration // Upper bound for a single delivery attempt
+	}
+	Webhooks struct {
+		Senders     int           // Deliveries sent in parallel
+		MaxAttempts int           // Delivery attempts before a webhook delivery is given up on
+		Timeout     time.Duration // Upper bound for a single delivery attempt
+	}
+	Outbound struct {
+		AllowedNetworks []netip.Prefix // Private networks notifications and webhooks may reach, e.g. local stand-ins
+	}
 }
 
 func Load() (*Config, error) {
@@ -62,5 +100,147 @@ func Load() (*Config, error) {
 		cfg.Server.NoSync = true
 	}
 
+	for _, id := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			cfg.Admin.UserIDs = append(cfg.Admin.UserIDs, id)
+		}
+	}
+
+	var err error
+	cfg.Match.Threshold, err = floatEnv("MATCH_THRESHOLD", 0.85)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Match.Threshold <= 0 || cfg.Match.Threshold > 1 {
+		return nil, fmt.Errorf("MATCH_THRESHOLD must be greater than 0 and at most 1")
+	}
+
+	cfg.Sync.Interval, err = durationEnv("SYNC_INTERVAL", 1*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Sync.Concurrency, err = intEnv("SYNC_CONCURRENCY", 4)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Sync.Concurrency < 1 {
+		return nil, fmt.Errorf("SYNC_CONCURRENCY must be at least 1")
+	}
+
+	cfg.Sync.GameTimeout, err = durationEnv("SYNC_GAME_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Sync.MaxFailures, err = intEnv("SYNC_MAX_FAILURES", 10)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Sync.MaxFailures < 1 {
+		return nil, fmt.Errorf("SYNC_MAX_FAILURES must be at least 1")
+	}
+
+	cfg.Sync.LeaseTTL, err = durationEnv("SYNC_LEASE_TTL", 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	if leaderElection := os.Getenv("SYNC_LEADER_ELECTION"); leaderElection == "0" || leaderElection == "false" {
+		cfg.Sync.LeaseTTL = 0
+	}
+
+	cfg.SMTP.Host = os.Getenv("SMTP_HOST")
+	cfg.SMTP.Username = os.Getenv("SMTP_USERNAME")
+	cfg.SMTP.Password = os.Getenv("SMTP_PASSWORD")
+	cfg.SMTP.From = os.Getenv("SMTP_FROM")
+	cfg.SMTP.Port, err = intEnv("SMTP_PORT", 587)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SMTP.Host != "" && cfg.SMTP.From == "" {
+		return nil, fmt.Errorf("SMTP_FROM is required when SMTP_HOST is set")
+	}
+
+	cfg.Notify.MaxAttempts, err = intEnv("NOTIFY_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Notify.MaxAttempts < 1 {
+		return nil, fmt.Errorf("NOTIFY_MAX_ATTEMPTS must be at least 1")
+	}
+
+	cfg.Notify.Timeout, err = durationEnv("NOTIFY_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Webhooks.Senders, err = intEnv("WEBHOOK_SENDERS", 4)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Webhooks.Senders < 1 {
+		return nil, fmt.Errorf("WEBHOOK_SENDERS must be at least 1")
+	}
+
+	cfg.Webhooks.MaxAttempts, err = intEnv("WEBHOOK_MAX_ATTEMPTS", 8)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Webhooks.MaxAttempts < 1 {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be at least 1")
+	}
+
+	cfg.Webhooks.Timeout, err = durationEnv("WEBHOOK_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Outbound.AllowedNetworks, err = netguard.ParsePrefixes(os.Getenv("OUTBOUND_ALLOWED_NETWORKS"))
+	if err != nil {
+		return nil, fmt.Errorf("OUTBOUND_ALLOWED_NETWORKS: %w", err)
+	}
+
 	return cfg, nil
 }
+
+// intEnv reads an integer environment variable, returning def when unset
+func intEnv(key string, def int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// floatEnv reads a decimal environment variable, returning def when unset
+func floatEnv(key string, def float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// durationEnv reads a duration environment variable (e.g. "30s", "1h"), returning def when unset
+func durationEnv(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration like 30s or 1h: %w", key, err)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("%s must be positive", key)
+	}
+	return parsed, nil
+}
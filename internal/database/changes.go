@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"game-tracker/internal/model"
+)
+
+// GameChangeType is the kind of write reported by WatchGames
+type GameChangeType string
+
+const (
+	GameCreated       GameChangeType = "created"
+	GameUpdated       GameChangeType = "updated"
+	GameStatusChanged GameChangeType = "status_changed"
+	GameDeleted       GameChangeType = "deleted"
+)
+
+// GameChange describes a write to one of a user's games
+type GameChange struct {
+	Type       GameChangeType
+	UserID     string
+	GameID     string
+	Game       *model.Game      // The game as written, for everything but deletes
+	Status     model.GameStatus // The new status, for status changes
+	DatePlayed *time.Time       // Set by status changes to a finished status
+	At         time.Time
+}
+
+// WatchGames listens to the user's games and calls fn for every change, whichever
+// process made it (API servers, the worker, backup restores), until ctx is cancelled or
+// the listener fails. The games as they are when it starts are the baseline and aren't
+// reported. Writes that only reschedule the game's sync aren't reported either.
+func (c *Client) WatchGames(ctx context.Context, userID string, fn func(GameChange)) error {
+	iter := c.firestore.Collection(gamesCollection).
+		Where("user_id", "==", userID).
+		Snapshots(ctx)
+	defer iter.Stop()
+
+	known := make(map[string]*model.Game)
+	baseline := true
+
+	for {
+		snapshot, err := iter.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("game listener for user %s failed: %w", userID, err)
+		}
+
+		for _, docChange := range snapshot.Changes {
+			gameID := docChange.Doc.Ref.ID
+			previous := known[gameID]
+
+			if docChange.Kind == firestore.DocumentRemoved {
+				delete(known, gameID)
+				if !baseline {
+					fn(GameChange{Type: GameDeleted, UserID: userID, GameID: gameID, At: snapshot.ReadTime})
+				}
+				continue
+			}
+
+			var game model.Game
+			if err := docChange.Doc.DataTo(&game); err != nil {
+				log.Printf("ERROR: Failed to parse game %s: %v", gameID, err)
+				continue
+			}
+			known[gameID] = &game
+			if baseline {
+				continue
+			}
+
+			change := GameChange{UserID: userID, GameID: gameID, Game: &game, At: game.UpdatedAt}
+			switch {
+			case previous == nil:
+				change.Type = GameCreated
+			case previous.Status != game.Status:
+				change.Type = GameStatusChanged
+				change.Status = game.Status
+				if game.HasDatePlayed() {
+					change.DatePlayed = game.DatePlayed
+				}
+			case scheduleOnly(previous, &game):
+				continue
+			default:
+				change.Type = GameUpdated
+			}
+			fn(change)
+		}
+
+		baseline = false
+	}
+}
+
+// scheduleOnly reports whether two versions of a game differ only in when the background
+// sync next looks at them, which users never see
+func scheduleOnly(before, after *model.Game) bool {
+	a, b := *before, *after
+	a.NextSyncAt, b.NextSyncAt = nil, nil
+	a.NextMatchAt, b.NextMatchAt = nil, nil
+	a.MatchAttempts, b.MatchAttempts = 0, 0
+	return reflect.DeepEqual(a, b)
+}
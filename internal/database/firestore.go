@@ -2,14 +2,19 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"time"
 
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go/v4"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"game-tracker/internal/config"
 	"game-tracker/internal/model"
@@ -71,18 +76,9 @@ func (c *Client) SaveGame(ctx context.Context, game *model.Game) error {
 	}
 	game.UpdatedAt = now
 
-	// Set sentinel values for nil dates to enable proper sorting in Firestore
-	// Games without release dates get far future date (sort to end)
-	if game.ReleaseDate == nil {
-		farFuture := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
-		game.ReleaseDate = &farFuture
-	}
-
-	// Games without date_played get epoch (sort to end when descending)
-	if game.DatePlayed == nil {
-		epoch := time.Unix(0, 0)
-		game.DatePlayed = &epoch
-	}
+	setDateSentinels(game)
+	setReleaseDays(game)
+	scheduleSync(game, now)
 
 	// Set match status if not already set
 	if game.MatchStatus == "" {
@@ -108,6 +104,82 @@ func (c *Client) SaveGame(ctx context.Context, game *model.Game) error {
 	return nil
 }
 
+// RestoreGame writes a game exactly as given (timestamps and match status untouched), for
+// restoring backups; only a missing sync schedule is filled in. It refuses to overwrite
+// another user's game.
+func (c *Client) RestoreGame(ctx context.Context, game *model.Game) error {
+	if game.ID == "" {
+		return fmt.Errorf("cannot restore game without ID")
+	}
+
+	setDateSentinels(game)
+	setReleaseDays(game)
+	scheduleSync(game, time.Now())
+
+	ref := c.firestore.Collection(gamesCollection).Doc(game.ID)
+	if err := c.restoreOwned(ctx, ref, game.UserID, game); err != nil {
+		return fmt.Errorf("failed to restore game: %w", err)
+	}
+
+	return nil
+}
+
+// ErrNotOwner is returned when a restore would overwrite another user's document
+var ErrNotOwner = errors.New("document belongs to another user")
+
+// restoreOwned writes data to ref unless the document already exists and belongs to a
+// different user. Archive IDs come from the uploaded file, so they can't be trusted to
+// point at the restoring user's documents. The check and write happen in one transaction.
+func (c *Client) restoreOwned(ctx context.Context, ref *firestore.DocumentRef, userID string, data interface{}) error {
+	return c.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if owner, _ := doc.Data()["user_id"].(string); owner != userID {
+				return fmt.Errorf("%s: %w", ref.ID, ErrNotOwner)
+			}
+		}
+		return tx.Set(ref, data)
+	})
+}
+
+// setDateSentinels sets sentinel values for nil dates to enable proper sorting in Firestore
+func setDateSentinels(game *model.Game) {
+	// Games without release dates get far future date (sort to end)
+	if game.ReleaseDate == nil {
+		farFuture := model.ReleaseDateSentinel
+		game.ReleaseDate = &farFuture
+	}
+
+	// Games without date_played get epoch (sort to end when descending)
+	if game.DatePlayed == nil {
+		epoch := model.DatePlayedSentinel
+		game.DatePlayed = &epoch
+	}
+}
+
+// setReleaseDays records the days the game has an exact release on, for
+// GetGamesReleasedOn
+func setReleaseDays(game *model.Game) {
+	var days []string
+	add := func(release model.PlatformRelease) {
+		if release.DatePrecision() != model.DatePrecisionDay {
+			return
+		}
+		if day := model.ReleaseDayKey(*release.Date); !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+
+	add(game.Release())
+	for _, release := range game.Releases {
+		add(release)
+	}
+	game.ReleaseDays = days
+}
+
 // GetGame retrieves a single game by ID
 func (c *Client) GetGame(ctx context.Context, gameID string) (*model.Game, error) {
 	doc, err := c.firestore.Collection(gamesCollection).Doc(gameID).Get(ctx)
@@ -146,6 +218,38 @@ func (c *Client) GetGameByIGDBID(ctx context.Context, userID string, igdbID int)
 	return &game, nil
 }
 
+// igdbIDBatchSize is Firestore's limit on values in an "in" filter
+const igdbIDBatchSize = 30
+
+// GetGamesByIGDBIDs finds the user's games with the given IGDB IDs, keyed by IGDB ID.
+// IDs not in the library are absent from the map.
+func (c *Client) GetGamesByIGDBIDs(ctx context.Context, userID string, igdbIDs []int) (map[int]*model.Game, error) {
+	games := make(map[int]*model.Game, len(igdbIDs))
+
+	for start := 0; start < len(igdbIDs); start += igdbIDBatchSize {
+		end := min(start+igdbIDBatchSize, len(igdbIDs))
+
+		docs, err := c.firestore.Collection(gamesCollection).
+			Where("user_id", "==", userID).
+			Where("igdb_id", "in", igdbIDs[start:end]).
+			Documents(ctx).GetAll()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to query games by IGDB ID: %w", err)
+		}
+
+		for _, doc := range docs {
+			var game model.Game
+			if err := doc.DataTo(&game); err != nil {
+				return nil, fmt.Errorf("failed to parse game: %w", err)
+			}
+			games[game.IGDBID] = &game
+		}
+	}
+
+	return games, nil
+}
+
 // GetGames retrieves games for a user with optional status filter
 func (c *Client) GetGames(ctx context.Context, userID string, statuses ...model.GameStatus) ([]*model.Game, error) {
 	query := c.firestore.Collection(gamesCollection).Where("user_id", "==", userID)
@@ -196,34 +300,6 @@ func (c *Client) GetBacklog(ctx context.Context, userID string) ([]*model.Game,
 	return games, nil
 }
 
-// GetUpcoming retrieves backlog games with release dates from the last month onwards, sorted by release date ASC
-func (c *Client) GetUpcoming(ctx context.Context, userID string) ([]*model.Game, error) {
-	// Calculate date one month ago
-	oneMonthAgo := time.Now().AddDate(0, -1, 0)
-
-	docs, err := c.firestore.Collection(gamesCollection).
-		Where("user_id", "==", userID).
-		Where("status", "in", []interface{}{model.StatusBacklog, model.StatusBreak}).
-		Where("release_date", ">=", oneMonthAgo).
-		OrderBy("release_date", firestore.Asc).
-		Documents(ctx).GetAll()
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to query upcoming games: %w", err)
-	}
-
-	games := make([]*model.Game, 0, len(docs))
-	for _, doc := range docs {
-		var game model.Game
-		if err := doc.DataTo(&game); err != nil {
-			return nil, fmt.Errorf("failed to parse game: %w", err)
-		}
-		games = append(games, &game)
-	}
-
-	return games, nil
-}
-
 // GetPlaying retrieves currently playing games sorted by updated_at DESC
 func (c *Client) GetPlaying(ctx context.Context, userID string) ([]*model.Game, error) {
 	docs, err := c.firestore.Collection(gamesCollection).
@@ -297,14 +373,69 @@ func (c *Client) GetAllGames(ctx context.Context, userID string) ([]*model.Game,
 	return games, nil
 }
 
-// GetGamesWithIGDBID retrieves all games that have an IGDB ID for background sync
-func (c *Client) GetGamesWithIGDBID(ctx context.Context) ([]*model.Game, error) {
+// ForEachGame streams every game for a user to fn in document order without loading the whole library into memory
+// Iteration stops at the first error returned by fn
+func (c *Client) ForEachGame(ctx context.Context, userID string, fn func(*model.Game) error) error {
+	iter := c.firestore.Collection(gamesCollection).
+		Where("user_id", "==", userID).
+		Documents(ctx)
+	defer iter.Stop()
+
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to iterate games: %w", err)
+		}
+
+		var game model.Game
+		if err := doc.DataTo(&game); err != nil {
+			return fmt.Errorf("failed to parse game: %w", err)
+		}
+
+		if err := fn(&game); err != nil {
+			return err
+		}
+	}
+}
+
+// GetGamesReleasedOn retrieves every user's Backlog and Break games with an exact release
+// on one of days (ReleaseDayKey, at most 30), for release day checks. The query uses
+// Firestore's automatic index on release_days; the few games it returns are filtered
+// by status here so no composite index is needed.
+func (c *Client) GetGamesReleasedOn(ctx context.Context, days []string) ([]*model.Game, error) {
 	docs, err := c.firestore.Collection(gamesCollection).
-		Where("igdb_id", ">", 0).
+		Where("release_days", "array-contains-any", days).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query released games: %w", err)
+	}
+
+	var games []*model.Game
+	for _, doc := range docs {
+		var game model.Game
+		if err := doc.DataTo(&game); err != nil {
+			return nil, fmt.Errorf("failed to parse game: %w", err)
+		}
+		if game.Status == model.StatusBacklog || game.Status == model.StatusBreak {
+			games = append(games, &game)
+		}
+	}
+
+	return games, nil
+}
+
+// GetGamesDueForSync retrieves matched games whose next_sync_at has passed. Games whose
+// sync was disabled have no next_sync_at, so the range filter leaves them out.
+func (c *Client) GetGamesDueForSync(ctx context.Context, now time.Time) ([]*model.Game, error) {
+	docs, err := c.firestore.Collection(gamesCollection).
+		Where("next_sync_at", "<=", now).
 		Documents(ctx).GetAll()
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to query games with IGDB ID: %w", err)
+		return nil, fmt.Errorf("failed to query games due for sync: %w", err)
 	}
 
 	games := make([]*model.Game, 0, len(docs))
@@ -313,14 +444,140 @@ func (c *Client) GetGamesWithIGDBID(ctx context.Context) ([]*model.Game, error)
 		if err := doc.DataTo(&game); err != nil {
 			return nil, fmt.Errorf("failed to parse game: %w", err)
 		}
+		if game.IGDBID == 0 || game.SyncDisabled {
+			continue
+		}
 		games = append(games, &game)
 	}
 
 	return games, nil
 }
 
+// BackfillSyncSchedule gives matched games written before every write set next_sync_at a
+// schedule, due at now, so GetGamesDueForSync finds them. It returns how many games it
+// updated; running it again updates none.
+func (c *Client) BackfillSyncSchedule(ctx context.Context, now time.Time) (int, error) {
+	iter := c.firestore.Collection(gamesCollection).
+		Where("igdb_id", ">", 0).
+		Documents(ctx)
+	defer iter.Stop()
+
+	updated := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return updated, nil
+		}
+		if err != nil {
+			return updated, fmt.Errorf("failed to query games with IGDB ID: %w", err)
+		}
+
+		var game model.Game
+		if err := doc.DataTo(&game); err != nil {
+			return updated, fmt.Errorf("failed to parse game %s: %w", doc.Ref.ID, err)
+		}
+		if game.NextSyncAt != nil || game.SyncDisabled {
+			continue
+		}
+
+		if _, err := doc.Ref.Update(ctx, []firestore.Update{{Path: "next_sync_at", Value: now}}); err != nil {
+			return updated, fmt.Errorf("failed to schedule game %s: %w", doc.Ref.ID, err)
+		}
+		updated++
+	}
+}
+
+// BackfillReleaseDays sets release_days on games written before every write kept it, so
+// GetGamesReleasedOn finds them. It returns how many games it updated; running it again
+// updates none.
+func (c *Client) BackfillReleaseDays(ctx context.Context) (int, error) {
+	iter := c.firestore.Collection(gamesCollection).Documents(ctx)
+	defer iter.Stop()
+
+	updated := 0
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return updated, nil
+		}
+		if err != nil {
+			return updated, fmt.Errorf("failed to query games: %w", err)
+		}
+
+		var game model.Game
+		if err := doc.DataTo(&game); err != nil {
+			return updated, fmt.Errorf("failed to parse game %s: %w", doc.Ref.ID, err)
+		}
+		stored := game.ReleaseDays
+		setReleaseDays(&game)
+		if slices.Equal(stored, game.ReleaseDays) {
+			continue
+		}
+
+		var days interface{} = firestore.Delete
+		if len(game.ReleaseDays) > 0 {
+			days = game.ReleaseDays
+		}
+		if _, err := doc.Ref.Update(ctx, []firestore.Update{{Path: "release_days", Value: days}}); err != nil {
+			return updated, fmt.Errorf("failed to set release days of game %s: %w", doc.Ref.ID, err)
+		}
+		updated++
+	}
+}
+
+// scheduleSync keeps next_sync_at in step with GetGamesDueForSync: matched games whose
+// sync isn't disabled always have one (due at now unless already scheduled), other games
+// have none
+func scheduleSync(game *model.Game, now time.Time) {
+	if game.IGDBID == 0 || game.SyncDisabled {
+		game.NextSyncAt = nil
+		return
+	}
+	if game.NextSyncAt == nil {
+		game.NextSyncAt = &now
+	}
+}
+
+// UpdateMatchCandidates stores the game's match candidates and reason without touching updated_at
+func (c *Client) UpdateMatchCandidates(ctx context.Context, game *model.Game) error {
+	_, err := c.firestore.Collection(gamesCollection).Doc(game.ID).Update(ctx, []firestore.Update{
+		{Path: "match_candidates", Value: game.MatchCandidates},
+		{Path: "match_reason", Value: game.MatchReason},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update match candidates: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSyncState writes the game's sync bookkeeping (next_sync_at, sync_failures,
+// sync_disabled, last_sync_error) without touching updated_at, so sync attempts that
+// change no metadata don't reorder the user's lists. A missing next_sync_at is written
+// as due now, unless the sync is disabled.
+func (c *Client) UpdateSyncState(ctx context.Context, game *model.Game) error {
+	scheduleSync(game, time.Now())
+	var nextSyncAt interface{} = firestore.Delete
+	if game.NextSyncAt != nil {
+		nextSyncAt = *game.NextSyncAt
+	}
+
+	_, err := c.firestore.Collection(gamesCollection).Doc(game.ID).Update(ctx, []firestore.Update{
+		{Path: "next_sync_at", Value: nextSyncAt},
+		{Path: "sync_failures", Value: game.SyncFailures},
+		{Path: "sync_disabled", Value: game.SyncDisabled},
+		{Path: "last_sync_error", Value: game.LastSyncError},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update sync state: %w", err)
+	}
+
+	return nil
+}
+
 // GetUnmatchedGames retrieves games without IGDB IDs (manual entries needing matching)
-func (c *Client) GetUnmatchedGames(ctx context.Context) ([]*model.Game, error) {
+// whose next match attempt is due at now
+func (c *Client) GetUnmatchedGames(ctx context.Context, now time.Time) ([]*model.Game, error) {
 	docs, err := c.firestore.Collection(gamesCollection).
 		Where("igdb_id", "==", 0).
 		Documents(ctx).GetAll()
@@ -335,17 +592,50 @@ func (c *Client) GetUnmatchedGames(ctx context.Context) ([]*model.Game, error) {
 		if err := doc.DataTo(&game); err != nil {
 			return nil, fmt.Errorf("failed to parse game: %w", err)
 		}
+		if game.NextMatchAt != nil && game.NextMatchAt.After(now) {
+			continue
+		}
 		games = append(games, &game)
 	}
 
 	return games, nil
 }
 
+// UpdateSearchHint stores the game's search hint and makes it due for matching again
+func (c *Client) UpdateSearchHint(ctx context.Context, game *model.Game) error {
+	_, err := c.firestore.Collection(gamesCollection).Doc(game.ID).Update(ctx, []firestore.Update{
+		{Path: "search_hint", Value: game.SearchHint},
+		{Path: "match_status", Value: game.MatchStatus},
+		{Path: "match_attempts", Value: game.MatchAttempts},
+		{Path: "next_match_at", Value: firestore.Delete},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update search hint: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePreferredPlatform updates only the platform the user plans to play the game on
+func (c *Client) UpdatePreferredPlatform(ctx context.Context, gameID, platform string) error {
+	_, err := c.firestore.Collection(gamesCollection).Doc(gameID).Update(ctx, []firestore.Update{
+		{Path: "preferred_platform", Value: platform},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update preferred platform: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateGameStatus updates only the status of a game
 func (c *Client) UpdateGameStatus(ctx context.Context, gameID string, status model.GameStatus, datePlayed *time.Time) error {
+	now := time.Now()
 	updates := []firestore.Update{
 		{Path: "status", Value: status},
-		{Path: "updated_at", Value: time.Now()},
+		{Path: "updated_at", Value: now},
 	}
 
 	// If status is being changed to a completed state, update date_played
@@ -361,7 +651,28 @@ func (c *Client) UpdateGameStatus(ctx context.Context, gameID string, status mod
 		updates = append(updates, firestore.Update{Path: "date_played", Value: playedDate})
 	}
 
-	_, err := c.firestore.Collection(gamesCollection).Doc(gameID).Update(ctx, updates)
+	ref := c.firestore.Collection(gamesCollection).Doc(gameID)
+	err := c.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		var game model.Game
+		if err := doc.DataTo(&game); err != nil {
+			return err
+		}
+
+		// The sync schedule depends on status, so make the game due and let the next run
+		// reschedule it
+		game.NextSyncAt = nil
+		scheduleSync(&game, now)
+		var nextSyncAt interface{} = firestore.Delete
+		if game.NextSyncAt != nil {
+			nextSyncAt = *game.NextSyncAt
+		}
+
+		return tx.Update(ref, append(updates, firestore.Update{Path: "next_sync_at", Value: nextSyncAt}))
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update game status: %w", err)
 	}
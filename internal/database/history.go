@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"game-tracker/internal/model"
+)
+
+const statusHistoryCollection = "status_history"
+
+// RecordStatusChange appends an entry to a game's status history
+func (c *Client) RecordStatusChange(ctx context.Context, change *model.StatusChange) error {
+	if change.ChangedAt.IsZero() {
+		change.ChangedAt = time.Now()
+	}
+
+	if change.ID == "" {
+		change.ID = c.firestore.Collection(statusHistoryCollection).NewDoc().ID
+	}
+
+	_, err := c.firestore.Collection(statusHistoryCollection).Doc(change.ID).Set(ctx, change)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+
+	return nil
+}
+
+// ForEachStatusChange streams a user's status history to fn, oldest first
+func (c *Client) ForEachStatusChange(ctx context.Context, userID string, fn func(*model.StatusChange) error) error {
+	iter := c.firestore.Collection(statusHistoryCollection).
+		Where("user_id", "==", userID).
+		OrderBy("changed_at", firestore.Asc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to iterate status history: %w", err)
+		}
+
+		var change model.StatusChange
+		if err := doc.DataTo(&change); err != nil {
+			return fmt.Errorf("failed to parse status change: %w", err)
+		}
+
+		if err := fn(&change); err != nil {
+			return err
+		}
+	}
+}
+
+// RestoreStatusChange writes a status history entry exactly as given, for restoring
+// backups. It refuses to overwrite another user's entry.
+func (c *Client) RestoreStatusChange(ctx context.Context, change *model.StatusChange) error {
+	if change.ID == "" {
+		return fmt.Errorf("cannot restore status change without ID")
+	}
+
+	ref := c.firestore.Collection(statusHistoryCollection).Doc(change.ID)
+	if err := c.restoreOwned(ctx, ref, change.UserID, change); err != nil {
+		return fmt.Errorf("failed to restore status change: %w", err)
+	}
+
+	return nil
+}
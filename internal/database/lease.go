@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const locksCollection = "locks"
+
+// lease is a named lock document; it is free once expires_at has passed
+type lease struct {
+	Holder    string    `firestore:"holder"`
+	ExpiresAt time.Time `firestore:"expires_at"`
+}
+
+// AcquireLease takes the named lease for holder when it is free, expired or already held
+// by holder (renewing it), and reports whether holder now owns it. The check and write
+// happen in one transaction, so at most one holder wins.
+func (c *Client) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	ref := c.firestore.Collection(locksCollection).Doc(name)
+	acquired := false
+
+	err := c.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		acquired = false
+		now := time.Now()
+
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var current lease
+			if err := doc.DataTo(&current); err != nil {
+				return err
+			}
+			if current.Holder != holder && current.ExpiresAt.After(now) {
+				return nil
+			}
+		}
+
+		acquired = true
+		return tx.Set(ref, lease{Holder: holder, ExpiresAt: now.Add(ttl)})
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+
+	return acquired, nil
+}
+
+// ReleaseLease frees the named lease if holder still owns it, so another holder can take
+// over without waiting for it to expire
+func (c *Client) ReleaseLease(ctx context.Context, name, holder string) error {
+	ref := c.firestore.Collection(locksCollection).Doc(name)
+
+	err := c.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var current lease
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+		if current.Holder != holder {
+			return nil
+		}
+
+		return tx.Delete(ref)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", name, err)
+	}
+
+	return nil
+}
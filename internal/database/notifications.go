@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"game-tracker/internal/model"
+)
+
+const deliveriesCollection = "notification_deliveries"
+
+// CreateDelivery stores a new delivery record under its ID. It returns false without
+// writing when a delivery with that ID exists, i.e. the event was already delivered
+// to the channel.
+func (c *Client) CreateDelivery(ctx context.Context, delivery *model.NotificationDelivery) (bool, error) {
+	if delivery.ID == "" {
+		return false, fmt.Errorf("cannot create delivery without ID")
+	}
+
+	_, err := c.firestore.Collection(deliveriesCollection).Doc(delivery.ID).Create(ctx, delivery)
+	if status.Code(err) == codes.AlreadyExists {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create delivery: %w", err)
+	}
+
+	return true, nil
+}
+
+// SaveDelivery replaces a delivery record, e.g. after an attempt
+func (c *Client) SaveDelivery(ctx context.Context, delivery *model.NotificationDelivery) error {
+	_, err := c.firestore.Collection(deliveriesCollection).Doc(delivery.ID).Set(ctx, delivery)
+	if err != nil {
+		return fmt.Errorf("failed to save delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueDeliveries returns pending deliveries whose next attempt is due
+func (c *Client) GetDueDeliveries(ctx context.Context, now time.Time) ([]*model.NotificationDelivery, error) {
+	docs, err := c.firestore.Collection(deliveriesCollection).
+		Where("status", "==", model.DeliveryPending).
+		Where("next_attempt_at", "<=", now).
+		Documents(ctx).GetAll()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due deliveries: %w", err)
+	}
+
+	return parseDeliveries(docs)
+}
+
+// GetDeliveries returns a user's latest deliveries, newest first
+func (c *Client) GetDeliveries(ctx context.Context, userID string, limit int) ([]*model.NotificationDelivery, error) {
+	docs, err := c.firestore.Collection(deliveriesCollection).
+		Where("user_id", "==", userID).
+		OrderBy("created_at", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deliveries: %w", err)
+	}
+
+	return parseDeliveries(docs)
+}
+
+func parseDeliveries(docs []*firestore.DocumentSnapshot) ([]*model.NotificationDelivery, error) {
+	deliveries := make([]*model.NotificationDelivery, 0, len(docs))
+	for _, doc := range docs {
+		var delivery model.NotificationDelivery
+		if err := doc.DataTo(&delivery); err != nil {
+			return nil, fmt.Errorf("failed to parse delivery: %w", err)
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
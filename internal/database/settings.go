@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"game-tracker/internal/model"
+)
+
+const settingsCollection = "user_settings"
+
+// GetSettings returns the user's settings, or defaults when none were saved yet
+func (c *Client) GetSettings(ctx context.Context, userID string) (*model.UserSettings, error) {
+	doc, err := c.firestore.Collection(settingsCollection).Doc(userID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return &model.UserSettings{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	var settings model.UserSettings
+	if err := doc.DataTo(&settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// GetSettingsByCalendarToken finds the settings holding an iCalendar feed token, or
+// returns nil when no user has it
+func (c *Client) GetSettingsByCalendarToken(ctx context.Context, token string) (*model.UserSettings, error) {
+	docs, err := c.firestore.Collection(settingsCollection).
+		Where("calendar_token", "==", token).
+		Limit(1).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query settings by calendar token: %w", err)
+	}
+
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	var settings model.UserSettings
+	if err := docs[0].DataTo(&settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SaveSettings replaces the user's settings document
+func (c *Client) SaveSettings(ctx context.Context, settings *model.UserSettings) error {
+	if settings.UserID == "" {
+		return fmt.Errorf("cannot save settings without user ID")
+	}
+
+	settings.UpdatedAt = time.Now()
+
+	_, err := c.firestore.Collection(settingsCollection).Doc(settings.UserID).Set(ctx, settings)
+	if err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreSettings writes settings exactly as given, for restoring backups
+func (c *Client) RestoreSettings(ctx context.Context, settings *model.UserSettings) error {
+	if settings.UserID == "" {
+		return fmt.Errorf("cannot restore settings without user ID")
+	}
+
+	_, err := c.firestore.Collection(settingsCollection).Doc(settings.UserID).Set(ctx, settings)
+	if err != nil {
+		return fmt.Errorf("failed to restore settings: %w", err)
+	}
+
+	return nil
+}
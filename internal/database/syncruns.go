@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"game-tracker/internal/model"
+)
+
+const syncRunsCollection = "sync_runs"
+
+// SaveSyncRun creates or replaces a sync run record
+func (c *Client) SaveSyncRun(ctx context.Context, run *model.SyncRun) error {
+	if run.ID == "" {
+		run.ID = c.firestore.Collection(syncRunsCollection).NewDoc().ID
+	}
+
+	_, err := c.firestore.Collection(syncRunsCollection).Doc(run.ID).Set(ctx, run)
+	if err != nil {
+		return fmt.Errorf("failed to save sync run: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecentSyncRuns returns the latest sync runs, newest first
+func (c *Client) GetRecentSyncRuns(ctx context.Context, limit int) ([]*model.SyncRun, error) {
+	docs, err := c.firestore.Collection(syncRunsCollection).
+		OrderBy("started_at", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync runs: %w", err)
+	}
+
+	runs := make([]*model.SyncRun, 0, len(docs))
+	for _, doc := range docs {
+		var run model.SyncRun
+		if err := doc.DataTo(&run); err != nil {
+			return nil, fmt.Errorf("failed to parse sync run: %w", err)
+		}
+		runs = append(runs, &run)
+	}
+
+	return runs, nil
+}
+
+// GetQueuedSyncRuns returns the runs waiting to start, oldest request first
+func (c *Client) GetQueuedSyncRuns(ctx context.Context) ([]*model.SyncRun, error) {
+	// Sorted here rather than in the query, which would need a composite index; the
+	// queue only ever holds a few runs
+	docs, err := c.firestore.Collection(syncRunsCollection).
+		Where("status", "==", model.SyncRunQueued).
+		Documents(ctx).GetAll()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query queued sync runs: %w", err)
+	}
+
+	runs := make([]*model.SyncRun, 0, len(docs))
+	for _, doc := range docs {
+		var run model.SyncRun
+		if err := doc.DataTo(&run); err != nil {
+			return nil, fmt.Errorf("failed to parse sync run: %w", err)
+		}
+		runs = append(runs, &run)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.Before(runs[j].StartedAt)
+	})
+	return runs, nil
+}
+
+// ClaimSyncRun moves a queued run to running, started at now, and returns it. It returns
+// nil when the run is no longer queued. The check and write happen in one transaction,
+// so a run is started once even if a new leader takes over mid-claim.
+func (c *Client) ClaimSyncRun(ctx context.Context, id string, now time.Time) (*model.SyncRun, error) {
+	ref := c.firestore.Collection(syncRunsCollection).Doc(id)
+	var claimed *model.SyncRun
+
+	err := c.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var run model.SyncRun
+		if err := doc.DataTo(&run); err != nil {
+			return err
+		}
+		if run.Status != model.SyncRunQueued {
+			return nil
+		}
+
+		run.Status = model.SyncRunRunning
+		run.StartedAt = now
+		claimed = &run
+		return tx.Set(ref, &run)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim sync run: %w", err)
+	}
+
+	return claimed, nil
+}
@@ -0,0 +1,216 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"game-tracker/internal/model"
+)
+
+const (
+	webhooksCollection          = "webhooks"
+	webhookDeliveriesCollection = "webhook_deliveries"
+)
+
+// SaveWebhookSubscription creates or replaces a webhook subscription
+func (c *Client) SaveWebhookSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	if sub.UserID == "" {
+		return fmt.Errorf("cannot save webhook subscription without user ID")
+	}
+
+	now := time.Now()
+	if sub.ID == "" {
+		sub.ID = c.firestore.Collection(webhooksCollection).NewDoc().ID
+		sub.CreatedAt = now
+	}
+	sub.UpdatedAt = now
+
+	_, err := c.firestore.Collection(webhooksCollection).Doc(sub.ID).Set(ctx, sub)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhookSubscription returns a subscription, or nil when it doesn't exist
+func (c *Client) GetWebhookSubscription(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	doc, err := c.firestore.Collection(webhooksCollection).Doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+
+	var sub model.WebhookSubscription
+	if err := doc.DataTo(&sub); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook subscription: %w", err)
+	}
+
+	return &sub, nil
+}
+
+// GetWebhookSubscriptions returns a user's subscriptions, oldest first
+func (c *Client) GetWebhookSubscriptions(ctx context.Context, userID string) ([]*model.WebhookSubscription, error) {
+	return c.queryWebhookSubscriptions(ctx, c.firestore.Collection(webhooksCollection).
+		Where("user_id", "==", userID).
+		OrderBy("created_at", firestore.Asc))
+}
+
+// GetActiveWebhookSubscriptions returns a user's subscriptions that receive events
+func (c *Client) GetActiveWebhookSubscriptions(ctx context.Context, userID string) ([]*model.WebhookSubscription, error) {
+	return c.queryWebhookSubscriptions(ctx, c.firestore.Collection(webhooksCollection).
+		Where("user_id", "==", userID).
+		Where("active", "==", true))
+}
+
+func (c *Client) queryWebhookSubscriptions(ctx context.Context, query firestore.Query) ([]*model.WebhookSubscription, error) {
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+
+	subs := make([]*model.WebhookSubscription, 0, len(docs))
+	for _, doc := range docs {
+		var sub model.WebhookSubscription
+		if err := doc.DataTo(&sub); err != nil {
+			return nil, fmt.Errorf("failed to parse webhook subscription: %w", err)
+		}
+		subs = append(subs, &sub)
+	}
+
+	return subs, nil
+}
+
+// DeleteWebhookSubscription deletes a subscription. Its delivery log is kept; pending
+// deliveries fail on their next attempt.
+func (c *Client) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	_, err := c.firestore.Collection(webhooksCollection).Doc(id).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// SaveWebhookDelivery creates or replaces a delivery record
+func (c *Client) SaveWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	if delivery.ID == "" {
+		delivery.ID = c.firestore.Collection(webhookDeliveriesCollection).NewDoc().ID
+	}
+
+	_, err := c.firestore.Collection(webhookDeliveriesCollection).Doc(delivery.ID).Set(ctx, delivery)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhookDelivery returns a delivery, or nil when it doesn't exist
+func (c *Client) GetWebhookDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	doc, err := c.firestore.Collection(webhookDeliveriesCollection).Doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	var delivery model.WebhookDelivery
+	if err := doc.DataTo(&delivery); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+// ClaimWebhookDelivery takes a pending delivery whose attempt is due for one sender by
+// pushing its next attempt to until, and returns it. It returns nil when the delivery
+// isn't due (already sent, or claimed by another sender or instance). The check and
+// write happen in one transaction, so each attempt is made once.
+func (c *Client) ClaimWebhookDelivery(ctx context.Context, id string, now, until time.Time) (*model.WebhookDelivery, error) {
+	ref := c.firestore.Collection(webhookDeliveriesCollection).Doc(id)
+	var claimed *model.WebhookDelivery
+
+	err := c.firestore.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var delivery model.WebhookDelivery
+		if err := doc.DataTo(&delivery); err != nil {
+			return err
+		}
+		if delivery.Status != model.DeliveryPending || delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(now) {
+			return nil
+		}
+
+		delivery.NextAttemptAt = &until
+		claimed = &delivery
+		return tx.Update(ref, []firestore.Update{{Path: "next_attempt_at", Value: until}})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// GetDueWebhookDeliveries returns the IDs of up to limit pending deliveries whose next
+// attempt is due
+func (c *Client) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	docs, err := c.firestore.Collection(webhookDeliveriesCollection).
+		Where("status", "==", model.DeliveryPending).
+		Where("next_attempt_at", "<=", now).
+		OrderBy("next_attempt_at", firestore.Asc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due webhook deliveries: %w", err)
+	}
+
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.Ref.ID)
+	}
+
+	return ids, nil
+}
+
+// GetWebhookDeliveries returns a subscription's latest deliveries, newest first
+func (c *Client) GetWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error) {
+	docs, err := c.firestore.Collection(webhookDeliveriesCollection).
+		Where("subscription_id", "==", subscriptionID).
+		OrderBy("created_at", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0, len(docs))
+	for _, doc := range docs {
+		var delivery model.WebhookDelivery
+		if err := doc.DataTo(&delivery); err != nil {
+			return nil, fmt.Errorf("failed to parse webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
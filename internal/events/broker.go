@@ -0,0 +1,246 @@
+// Package events fans game changes out to the signed-in user's open connections (the
+// server-sent events stream), keeping recent events so reconnecting clients can resume
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	TypeGameCreated       = "game.created"
+	TypeGameUpdated       = "game.updated"
+	TypeGameStatusChanged = "game.status_changed"
+	TypeGameDeleted       = "game.deleted"
+	TypeReset             = "reset" // Missed events can't be replayed; reload everything
+)
+
+const (
+	historySize        = 1000        // Recent events kept for resuming, across all users
+	subscriptionBuffer = 64          // Events a slow connection may fall behind before it is dropped
+	watchLinger        = time.Minute // A user's watch outlives their last connection this long, so reloads can resume
+)
+
+// Event is one published change
+type Event struct {
+	ID     string // "<epoch>-<sequence>", for Last-Event-ID
+	Type   string
+	Data   []byte // JSON
+	userID string
+	seq    uint64
+}
+
+// Subscription is one open connection's feed of its user's events. Events is closed when
+// the broker drops a subscriber that fell too far behind; the client then reconnects
+// and resumes.
+type Subscription struct {
+	Events <-chan Event
+	events chan Event
+	userID string
+}
+
+// Watcher follows one user's changes, wherever they are made, and publishes them until
+// ctx is cancelled
+type Watcher func(ctx context.Context, userID string, publish func(eventType string, data any))
+
+// watch is a running Watcher for a user with open connections
+type watch struct {
+	cancel context.CancelFunc
+	since  uint64      // First sequence the watch could have published
+	linger *time.Timer // Set while the user has no connections
+}
+
+// Broker is a pub/sub of per-user events. While a user has open connections, the broker
+// runs a Watcher for them, so changes made by any process reach their streams.
+type Broker struct {
+	mu      sync.Mutex
+	watcher Watcher
+	epoch   string // Distinguishes this process's event IDs from an earlier one's
+	seq     uint64
+	history []Event // Oldest first
+	evicted uint64  // Highest sequence dropped from history
+	subs    map[string]map[*Subscription]struct{}
+	watches map[string]*watch
+	closed  bool
+}
+
+// NewBroker returns a broker that follows each connected user with watcher. With a nil
+// watcher, only events passed to Publish are delivered.
+func NewBroker(watcher Watcher) *Broker {
+	return &Broker{
+		watcher: watcher,
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		subs:    make(map[string]map[*Subscription]struct{}),
+		watches: make(map[string]*watch),
+	}
+}
+
+// Publish sends an event to the user's subscribers. data is encoded right away, so the
+// caller may keep changing it.
+func (b *Broker) Publish(userID, eventType string, data any) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("ERROR: Failed to encode %s event: %v", eventType, err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event := Event{
+		ID:     b.epoch + "-" + strconv.FormatUint(b.seq, 10),
+		Type:   eventType,
+		Data:   encoded,
+		userID: userID,
+		seq:    b.seq,
+	}
+
+	if len(b.history) == historySize {
+		b.evicted = b.history[0].seq
+		b.history = append(b.history[:0], b.history[1:]...)
+	}
+	b.history = append(b.history, event)
+
+	for sub := range b.subs[userID] {
+		select {
+		case sub.events <- event:
+		default:
+			// Blocking here would stall every publisher; the client resumes from its
+			// last event instead
+			b.remove(sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe opens a feed of the user's events. With a lastEventID from an earlier
+// connection, it also returns the events published since; resumed is false when those
+// can't all be replayed (the server restarted, too much happened, or nobody was watching
+// the user's changes in between), and the client should reload instead.
+func (b *Broker) Subscribe(userID, lastEventID string) (sub *Subscription, replay []Event, resumed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(chan Event, subscriptionBuffer)
+	sub = &Subscription{Events: events, events: events, userID: userID}
+	if b.closed {
+		close(events)
+		return sub, nil, true
+	}
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*Subscription]struct{})
+	}
+	b.subs[userID][sub] = struct{}{}
+	w := b.startWatch(userID)
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+
+	epoch, seqText, ok := strings.Cut(lastEventID, "-")
+	last, err := strconv.ParseUint(seqText, 10, 64)
+	if !ok || err != nil || epoch != b.epoch || last > b.seq || last < b.evicted || last < w.since {
+		return sub, nil, false
+	}
+
+	for _, event := range b.history {
+		if event.seq > last && event.userID == userID {
+			replay = append(replay, event)
+		}
+	}
+	return sub, replay, true
+}
+
+// Unsubscribe closes a feed when its connection ends
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub.userID][sub]; ok {
+		b.remove(sub)
+		close(sub.events)
+	}
+}
+
+// Close ends every subscription, so open streams finish and the server can shut down;
+// clients reconnect elsewhere and resume
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for userID, w := range b.watches {
+		b.stopWatch(userID, w)
+	}
+	for _, subs := range b.subs {
+		for sub := range subs {
+			b.remove(sub)
+			close(sub.events)
+		}
+	}
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	delete(b.subs[sub.userID], sub)
+	if len(b.subs[sub.userID]) == 0 {
+		delete(b.subs, sub.userID)
+		b.lingerWatch(sub.userID)
+	}
+}
+
+// startWatch returns the user's watch, starting one if needed. Called with mu held.
+func (b *Broker) startWatch(userID string) *watch {
+	if b.watcher == nil {
+		return &watch{}
+	}
+	if w, ok := b.watches[userID]; ok {
+		if w.linger != nil {
+			w.linger.Stop()
+			w.linger = nil
+		}
+		return w
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &watch{cancel: cancel, since: b.seq + 1}
+	b.watches[userID] = w
+	go b.watcher(ctx, userID, func(eventType string, data any) {
+		b.Publish(userID, eventType, data)
+	})
+	return w
+}
+
+// lingerWatch stops the user's watch once they have had no connections for watchLinger.
+// Called with mu held.
+func (b *Broker) lingerWatch(userID string) {
+	w, ok := b.watches[userID]
+	if !ok || w.linger != nil {
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(watchLinger, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// A subscriber may have arrived after the timer fired but before this ran
+		if b.watches[userID] == w && w.linger == timer {
+			b.stopWatch(userID, w)
+		}
+	})
+	w.linger = timer
+}
+
+// stopWatch cancels a watch. Called with mu held.
+func (b *Broker) stopWatch(userID string, w *watch) {
+	if w.linger != nil {
+		w.linger.Stop()
+	}
+	w.cancel()
+	delete(b.watches, userID)
+}
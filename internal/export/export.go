@@ -0,0 +1,323 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"game-tracker/internal/model"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat validates an export format name, defaulting to CSV when empty
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported export format: %q", s)
+	}
+}
+
+// ContentType returns the MIME type for the format
+func (f Format) ContentType() string {
+	if f == FormatJSON {
+		return "application/json"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// listSeparator joins multi-valued fields (genres, platforms) inside a single CSV cell
+const listSeparator = "|"
+
+// Columns is the stable CSV column order. Append new fields at the end so existing
+// spreadsheets and scripts keep working.
+var Columns = []string{
+	"id",
+	"user_id",
+	"title",
+	"igdb_id",
+	"cover_url",
+	"rating",
+	"status",
+	"genres",
+	"platforms",
+	"release_date",
+	"date_played",
+	"steam_url",
+	"official_url",
+	"match_status",
+	"created_at",
+	"updated_at",
+	"last_sync_error",
+	"user_rating",
+	"next_sync_at",
+	"sync_failures",
+	"sync_disabled",
+	"search_hint",
+	"match_attempts",
+	"next_match_at",
+	"preferred_platform",
+	"release_precision",
+	"release_label",
+	"igdb_url",
+	"time_to_beat_hastily_hours",
+	"time_to_beat_normally_hours",
+	"time_to_beat_completely_hours",
+	"game_type",
+	"parent_game",
+	"parent_igdb_id",
+	"franchises",
+	"collections",
+	"developers",
+	"publishers",
+	"summary",
+	"storyline",
+	"themes",
+	"game_modes",
+	"player_perspectives",
+	"imported_steam_url",
+	"releases",
+	"match_candidates",
+	"match_reason",
+	"dlcs",
+	"expansions",
+	"remasters",
+	"relation",
+	"screenshots",
+	"videos",
+}
+
+// Writer writes games one at a time so callers can stream straight from the database
+type Writer interface {
+	Write(game *model.Game) error
+	// Close finishes the document and flushes any buffered output
+	Close() error
+}
+
+// NewWriter returns a streaming writer for the given format
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %q", format)
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Columns); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) Write(game *model.Game) error {
+	game = withoutSentinels(game)
+
+	record := []string{
+		game.ID,
+		game.UserID,
+		game.Title,
+		formatInt(game.IGDBID),
+		game.CoverURL,
+		formatInt(game.Rating),
+		string(game.Status),
+		strings.Join(game.Genres, listSeparator),
+		strings.Join(game.Platforms, listSeparator),
+		formatDate(game.ReleaseDate),
+		formatDate(game.DatePlayed),
+		game.SteamURL,
+		game.OfficialURL,
+		string(game.MatchStatus),
+		formatTimestamp(game.CreatedAt),
+		formatTimestamp(game.UpdatedAt),
+		game.LastSyncError,
+		formatInt(game.UserRating),
+		formatDate(game.NextSyncAt),
+		formatInt(game.SyncFailures),
+		formatBool(game.SyncDisabled),
+		game.SearchHint,
+		formatInt(game.MatchAttempts),
+		formatDate(game.NextMatchAt),
+		game.PreferredPlatform,
+		string(game.ReleasePrecision),
+		game.ReleaseLabel,
+		game.IGDBURL,
+		formatHours(game.TimeToBeat, model.PaceHastily),
+		formatHours(game.TimeToBeat, model.PaceNormally),
+		formatHours(game.TimeToBeat, model.PaceCompletely),
+		game.GameType,
+		parentName(game.ParentGame),
+		parentIGDBID(game.ParentGame),
+		strings.Join(game.Franchises, listSeparator),
+		strings.Join(game.Collections, listSeparator),
+		strings.Join(game.Developers, listSeparator),
+		strings.Join(game.Publishers, listSeparator),
+		game.Summary,
+		game.Storyline,
+		strings.Join(game.Themes, listSeparator),
+		strings.Join(game.GameModes, listSeparator),
+		strings.Join(game.PlayerPerspectives, listSeparator),
+		game.ImportedSteamURL,
+		formatJSON(game.Releases),
+		formatJSON(game.MatchCandidates),
+		game.MatchReason,
+		formatJSON(game.DLCs),
+		formatJSON(game.Expansions),
+		formatJSON(game.Remasters),
+		relationKind(game),
+		strings.Join(game.Screenshots, listSeparator),
+		formatJSON(game.Videos),
+	}
+
+	if err := c.w.Write(record); err != nil {
+		return fmt.Errorf("failed to write CSV row: %w", err)
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonWriter emits a JSON array element by element instead of encoding a full slice
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonWriter) Write(game *model.Game) error {
+	prefix := ",\n"
+	if j.count == 0 {
+		prefix = "[\n"
+	}
+
+	data, err := json.Marshal(withoutSentinels(game))
+	if err != nil {
+		return fmt.Errorf("failed to encode game %s: %w", game.ID, err)
+	}
+
+	if _, err := io.WriteString(j.w, prefix); err != nil {
+		return err
+	}
+	if _, err := j.w.Write(data); err != nil {
+		return err
+	}
+
+	j.count++
+	return nil
+}
+
+func (j *jsonWriter) Close() error {
+	closing := "\n]\n"
+	if j.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
+
+// withoutSentinels returns a copy of the game with database sentinel dates cleared
+func withoutSentinels(game *model.Game) *model.Game {
+	out := *game
+	if !game.HasReleaseDate() {
+		out.ReleaseDate = nil
+	}
+	if !game.HasDatePlayed() {
+		out.DatePlayed = nil
+	}
+	return &out
+}
+
+// formatJSON encodes a list of structured values (releases, candidates, related games)
+// into a single CSV cell as a JSON array, or returns "" for an empty list
+func formatJSON[T any](values []T) string {
+	if len(values) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// relationKind is how the game relates to its parent game (e.g. "dlc"), or "" for none
+func relationKind(game *model.Game) string {
+	if relation := game.ParentRelation(); relation != nil {
+		return string(relation.Kind)
+	}
+	return ""
+}
+
+func formatInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+func parentName(parent *model.GameRef) string {
+	if parent == nil {
+		return ""
+	}
+	return parent.Name
+}
+
+func parentIGDBID(parent *model.GameRef) string {
+	if parent == nil {
+		return ""
+	}
+	return formatInt(parent.IGDBID)
+}
+
+// formatHours writes a time-to-beat estimate in hours, to one decimal
+func formatHours(ttb *model.TimeToBeat, pace model.Pace) string {
+	hours, ok := ttb.Hours(pace)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(hours, 'f', 1, 64)
+}
+
+func formatBool(v bool) string {
+	if !v {
+		return ""
+	}
+	return "true"
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
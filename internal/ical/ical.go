@@ -0,0 +1,102 @@
+// Package ical writes iCalendar (RFC 5545) feeds of all-day events
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding
+const maxLineOctets = 75
+
+// Event is an all-day event covering Start up to (not including) End
+type Event struct {
+	UID         string // Stable across feed refreshes, so clients update moved events
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time // Only the date is used
+	End         time.Time // Exclusive; only the date is used
+	Stamp       time.Time // When the event last changed
+}
+
+// Calendar is a named feed of events
+type Calendar struct {
+	ProductID string // e.g. "-//game-tracker//Release Calendar//EN"
+	Name      string
+	Events    []Event
+}
+
+// Write renders the calendar as an iCalendar document
+func Write(w io.Writer, cal *Calendar) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", cal.ProductID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+
+	for _, event := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escape(event.UID))
+		line("DTSTAMP", event.Stamp.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE", event.Start.Format("20060102"))
+		line("DTEND;VALUE=DATE", event.End.Format("20060102"))
+		line("SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escape(event.Description))
+		}
+		if event.URL != "" {
+			line("URL", event.URL)
+		}
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+// escape escapes a TEXT value
+func escape(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// writeFolded writes a content line, folding it into 75-octet lines (continuations start
+// with a space) without splitting UTF-8 characters
+func writeFolded(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // The leading space counts
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
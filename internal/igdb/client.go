@@ -1,20 +1,27 @@
 package igdb
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 const (
 	authURL = "https://id.twitch.tv/oauth2/token"
 	apiURL  = "https://api.igdb.com/v4/"
 	retries = 3
+
+	// IGDB allows 4 requests per second per client
+	requestsPerSecond = 4
 )
 
 type Client struct {
@@ -23,6 +30,7 @@ type Client struct {
 	accessToken        string
 	accessTokenExpires int64
 	httpClient         *http.Client
+	limiter            *rate.Limiter // Shared by every caller (API handlers and sync workers)
 	mu                 sync.Mutex
 }
 
@@ -37,6 +45,8 @@ type SearchCandidate struct {
 	Name        string `json:"name"`
 	CoverURL    string `json:"cover_url"`
 	ReleaseYear int    `json:"release_year"`
+	GameType    string `json:"game_type,omitempty"`
+	MatchedName string `json:"matched_name,omitempty"` // Alternative name that matched the query, if any
 }
 
 func NewClient(clientID, clientSecret string) *Client {
@@ -46,6 +56,7 @@ func NewClient(clientID, clientSecret string) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), requestsPerSecond),
 	}
 }
 
@@ -78,7 +89,7 @@ func (c *Client) refreshToken() error {
 	return nil
 }
 
-func (c *Client) request(endpoint, query string) (*http.Response, error) {
+func (c *Client) request(ctx context.Context, endpoint, query string) (*http.Response, error) {
 	c.mu.Lock()
 	if c.accessToken == "" || c.accessTokenExpires-3600 < time.Now().Unix() {
 		if err := c.refreshToken(); err != nil {
@@ -89,33 +100,39 @@ func (c *Client) request(endpoint, query string) (*http.Response, error) {
 	token := c.accessToken
 	c.mu.Unlock()
 
-	req, err := http.NewRequest("POST", apiURL+endpoint, bytes.NewBufferString(query))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Client-ID", c.clientID)
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "text/plain")
-
-	var resp *http.Response
 	tryCount := 0
 
 	for {
-		resp, err = c.httpClient.Do(req)
+		// Every attempt, retries included, counts against the shared rate limit
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", apiURL+endpoint, strings.NewReader(query))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Client-ID", c.clientID)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "text/plain")
+
+		resp, err := c.httpClient.Do(req)
 		if err != nil {
 			return nil, fmt.Errorf("failed to make request: %w", err)
 		}
 
 		if resp.StatusCode == http.StatusOK {
-			break
+			return resp, nil
 		}
 
 		if resp.StatusCode == http.StatusTooManyRequests {
 			resp.Body.Close()
 			tryCount = 0
 			log.Printf("[IGDB] Rate limited, retrying after 250ms...")
-			time.Sleep(250 * time.Millisecond)
+			if err := sleep(ctx, 250*time.Millisecond); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
@@ -129,12 +146,23 @@ func (c *Client) request(endpoint, query string) (*http.Response, error) {
 		resp.Body.Close()
 		log.Printf("[IGDB] Request failed with status %d, retry %d/%d", resp.StatusCode, tryCount, retries)
 	}
+}
 
-	return resp, nil
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
-func (c *Client) Request(endpoint, query string) ([]byte, error) {
-	resp, err := c.request(endpoint, query)
+func (c *Client) Request(ctx context.Context, endpoint, query string) ([]byte, error) {
+	resp, err := c.request(ctx, endpoint, query)
 	if err != nil {
 		return nil, err
 	}
@@ -149,11 +177,11 @@ func (c *Client) Request(endpoint, query string) ([]byte, error) {
 }
 
 // Search performs a search query on IGDB and returns minimal candidate results
-func (c *Client) Search(query string) ([]SearchCandidate, error) {
+func (c *Client) Search(ctx context.Context, query string) ([]SearchCandidate, error) {
 	log.Printf("[IGDB] Searching for: %q", query)
-	searchQuery := fmt.Sprintf(`fields game.name,game.cover.*,game.first_release_date; search "%s"; where game != null & game.game_type.type != (13) & game.version_parent = null; limit 10;`, query)
+	searchQuery := fmt.Sprintf(`fields game.name,game.cover.*,game.first_release_date,game.game_type.type; search "%s"; where game != null & game.game_type.type != (13) & game.version_parent = null; limit 10;`, escapeQuery(query))
 
-	body, err := c.Request("search", searchQuery)
+	body, err := c.Request(ctx, "search", searchQuery)
 	if err != nil {
 		log.Printf("[IGDB] Search failed for %q: %v", query, err)
 		return nil, fmt.Errorf("failed to search games: %w", err)
@@ -169,34 +197,77 @@ func (c *Client) Search(query string) ([]SearchCandidate, error) {
 		if result.Game == nil {
 			continue
 		}
+		candidates = append(candidates, newSearchCandidate(result.Game))
+	}
 
-		candidate := SearchCandidate{
-			ID:   result.Game.ID,
-			Name: result.Game.Name,
-		}
+	log.Printf("[IGDB] Search for %q returned %d results", query, len(candidates))
+	return candidates, nil
+}
 
-		if result.Game.Cover != nil {
-			candidate.CoverURL = result.Game.Cover.CoverBig2xURL()
-		}
+// SearchAlternativeNames finds games with an alternative name (abbreviation, localized or
+// working title) containing query; each candidate's MatchedName is the name that matched
+func (c *Client) SearchAlternativeNames(ctx context.Context, query string) ([]SearchCandidate, error) {
+	log.Printf("[IGDB] Searching alternative names for: %q", query)
+	searchQuery := fmt.Sprintf(`fields name,game.name,game.cover.*,game.first_release_date,game.game_type.type; where name ~ *"%s"* & game != null & game.version_parent = null; limit 10;`, escapeQuery(query))
 
-		if result.Game.FirstReleaseDate != nil {
-			releaseTime := time.Unix(*result.Game.FirstReleaseDate, 0)
-			candidate.ReleaseYear = releaseTime.Year()
-		}
+	body, err := c.Request(ctx, "alternative_names", searchQuery)
+	if err != nil {
+		log.Printf("[IGDB] Alternative name search failed for %q: %v", query, err)
+		return nil, fmt.Errorf("failed to search alternative names: %w", err)
+	}
+
+	var results []AlternativeName
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alternative names response: %w", err)
+	}
 
+	candidates := make([]SearchCandidate, 0, len(results))
+	for _, result := range results {
+		if result.Game == nil {
+			continue
+		}
+		candidate := newSearchCandidate(result.Game)
+		candidate.MatchedName = result.Name
 		candidates = append(candidates, candidate)
 	}
 
-	log.Printf("[IGDB] Search for %q returned %d results", query, len(candidates))
+	log.Printf("[IGDB] Alternative name search for %q returned %d results", query, len(candidates))
 	return candidates, nil
 }
 
+func newSearchCandidate(game *Game) SearchCandidate {
+	candidate := SearchCandidate{
+		ID:   game.ID,
+		Name: game.Name,
+	}
+
+	if game.Cover != nil {
+		candidate.CoverURL = game.Cover.CoverBig2xURL()
+	}
+
+	if game.FirstReleaseDate != nil {
+		releaseTime := time.Unix(*game.FirstReleaseDate, 0)
+		candidate.ReleaseYear = releaseTime.Year()
+	}
+
+	if game.GameType != nil {
+		candidate.GameType = game.GameType.Type
+	}
+
+	return candidate
+}
+
+// escapeQuery escapes a user-supplied string for use inside an Apicalypse string literal
+func escapeQuery(query string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(query)
+}
+
 // GetGameByID fetches full game details by IGDB ID
-func (c *Client) GetGameByID(id int) (*Game, error) {
+func (c *Client) GetGameByID(ctx context.Context, id int) (*Game, error) {
 	log.Printf("[IGDB] Fetching game details for ID: %d", id)
-	query := fmt.Sprintf(`fields name,url,aggregated_rating,category,first_release_date,platforms.*,cover.*,genres.*,websites.*,game_type.*,release_dates.*,release_dates.status.*,release_dates.platform.*,parent_game.id,parent_game.name,url,updated_at; where id = %d;`, id)
+	query := fmt.Sprintf(`fields name,url,aggregated_rating,category,first_release_date,platforms.*,cover.*,genres.*,websites.*,game_type.*,release_dates.*,release_dates.status.*,release_dates.platform.*,parent_game.id,parent_game.name,franchise.name,franchises.name,collections.name,dlcs.id,dlcs.name,expansions.id,expansions.name,standalone_expansions.id,standalone_expansions.name,remasters.id,remasters.name,remakes.id,remakes.name,summary,storyline,involved_companies.company.name,involved_companies.developer,involved_companies.publisher,themes.name,game_modes.name,player_perspectives.name,screenshots.image_id,videos.name,videos.video_id,updated_at; where id = %d;`, id)
 
-	body, err := c.Request("games", query)
+	body, err := c.Request(ctx, "games", query)
 	if err != nil {
 		log.Printf("[IGDB] Failed to fetch game ID %d: %v", id, err)
 		return nil, fmt.Errorf("failed to fetch game: %w", err)
@@ -213,6 +284,87 @@ func (c *Client) GetGameByID(id int) (*Game, error) {
 	}
 
 	log.Printf("[IGDB] Successfully fetched game: %s (ID: %d)", games[0].Name, id)
-
 	return games[0], nil
 }
+
+// timeToBeatBatchSize stays under IGDB's 500 results per request limit
+const timeToBeatBatchSize = 500
+
+// GetTimeToBeats fetches playtime estimates for many games at once, one request per
+// timeToBeatBatchSize games. Games IGDB has no estimates for are absent from the map.
+func (c *Client) GetTimeToBeats(ctx context.Context, gameIDs []int) (map[int]*TimeToBeat, error) {
+	timeToBeats := make(map[int]*TimeToBeat, len(gameIDs))
+
+	for start := 0; start < len(gameIDs); start += timeToBeatBatchSize {
+		end := min(start+timeToBeatBatchSize, len(gameIDs))
+
+		ids := make([]string, 0, end-start)
+		for _, id := range gameIDs[start:end] {
+			ids = append(ids, strconv.Itoa(id))
+		}
+
+		query := fmt.Sprintf(`fields game_id,hastily,normally,completely,count; where game_id = (%s); limit %d;`,
+			strings.Join(ids, ","), timeToBeatBatchSize)
+
+		body, err := c.Request(ctx, "game_time_to_beats", query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch time to beat: %w", err)
+		}
+
+		var results []*TimeToBeat
+		if err := json.Unmarshal(body, &results); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal time to beat response: %w", err)
+		}
+
+		for _, result := range results {
+			timeToBeats[result.GameID] = result
+		}
+	}
+
+	log.Printf("[IGDB] Fetched time to beat for %d of %d games", len(timeToBeats), len(gameIDs))
+	return timeToBeats, nil
+}
+
+// steamLookupBatchSize stays under IGDB's 500 results per request limit
+const steamLookupBatchSize = 500
+
+// GetGamesBySteamAppIDs maps Steam app IDs to IGDB games through the external_games endpoint
+// App IDs unknown to IGDB are absent from the returned map
+func (c *Client) GetGamesBySteamAppIDs(ctx context.Context, appIDs []int) (map[int]*Game, error) {
+	games := make(map[int]*Game, len(appIDs))
+
+	for start := 0; start < len(appIDs); start += steamLookupBatchSize {
+		end := min(start+steamLookupBatchSize, len(appIDs))
+
+		uids := make([]string, 0, end-start)
+		for _, appID := range appIDs[start:end] {
+			uids = append(uids, fmt.Sprintf("%q", strconv.Itoa(appID)))
+		}
+
+		log.Printf("[IGDB] Looking up %d Steam app IDs", len(uids))
+		query := fmt.Sprintf(`fields uid,game.id,game.name; where external_game_source = %d & uid = (%s); limit %d;`,
+			ExternalGameSourceSteam, strings.Join(uids, ","), steamLookupBatchSize)
+
+		body, err := c.Request(ctx, "external_games", query)
+		if err != nil {
+			log.Printf("[IGDB] Steam app lookup failed: %v", err)
+			return nil, fmt.Errorf("failed to look up Steam games: %w", err)
+		}
+
+		var results []ExternalGame
+		if err := json.Unmarshal(body, &results); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal external games response: %w", err)
+		}
+
+		for _, result := range results {
+			appID, err := strconv.Atoi(result.UID)
+			if err != nil || result.Game == nil {
+				continue
+			}
+			games[appID] = result.Game
+		}
+	}
+
+	log.Printf("[IGDB] Matched %d of %d Steam app IDs", len(games), len(appIDs))
+	return games, nil
+}
@@ -19,6 +19,23 @@ func (c *Cover) CoverBig2xURL() string {
 	return "https://images.igdb.com/igdb/image/upload/t_cover_big_2x/" + c.ImageID + ".jpg"
 }
 
+// Screenshot is an IGDB screenshot image
+type Screenshot struct {
+	ID      int    `json:"id"`
+	ImageID string `json:"image_id"`
+}
+
+func (s *Screenshot) ScreenshotBigURL() string {
+	return "https://images.igdb.com/igdb/image/upload/t_screenshot_big/" + s.ImageID + ".jpg"
+}
+
+// Video is an IGDB game video; VideoID is a YouTube video ID
+type Video struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	VideoID string `json:"video_id"`
+}
+
 type Platform struct {
 	ID           int    `json:"id"`
 	Name         string `json:"name"`
@@ -39,6 +56,50 @@ type ReleaseDateStatusType string
 type ReleaseCategory int
 type ReleaseRegion int
 
+// IGDB release date formats (release_dates.date_format), i.e. how exactly the date is known
+const (
+	ReleaseDateFormatDay   ReleaseCategory = 0 // YYYYMMMMDD
+	ReleaseDateFormatMonth ReleaseCategory = 1 // YYYYMMMM
+	ReleaseDateFormatYear  ReleaseCategory = 2 // YYYY
+	ReleaseDateFormatQ1    ReleaseCategory = 3 // YYYYQ1
+	ReleaseDateFormatQ2    ReleaseCategory = 4
+	ReleaseDateFormatQ3    ReleaseCategory = 5
+	ReleaseDateFormatQ4    ReleaseCategory = 6
+	ReleaseDateFormatTBD   ReleaseCategory = 7
+)
+
+// IGDB release regions (release_date_regions)
+const (
+	ReleaseRegionEurope       ReleaseRegion = 1
+	ReleaseRegionNorthAmerica ReleaseRegion = 2
+	ReleaseRegionAustralia    ReleaseRegion = 3
+	ReleaseRegionNewZealand   ReleaseRegion = 4
+	ReleaseRegionJapan        ReleaseRegion = 5
+	ReleaseRegionChina        ReleaseRegion = 6
+	ReleaseRegionAsia         ReleaseRegion = 7
+	ReleaseRegionWorldwide    ReleaseRegion = 8
+	ReleaseRegionKorea        ReleaseRegion = 9
+	ReleaseRegionBrazil       ReleaseRegion = 10
+)
+
+var releaseRegionNames = map[ReleaseRegion]string{
+	ReleaseRegionEurope:       "Europe",
+	ReleaseRegionNorthAmerica: "North America",
+	ReleaseRegionAustralia:    "Australia",
+	ReleaseRegionNewZealand:   "New Zealand",
+	ReleaseRegionJapan:        "Japan",
+	ReleaseRegionChina:        "China",
+	ReleaseRegionAsia:         "Asia",
+	ReleaseRegionWorldwide:    "Worldwide",
+	ReleaseRegionKorea:        "Korea",
+	ReleaseRegionBrazil:       "Brazil",
+}
+
+// String returns the region's name, or "" for regions IGDB doesn't document
+func (r ReleaseRegion) String() string {
+	return releaseRegionNames[r]
+}
+
 type ReleaseDateStatus struct {
 	ID   int                   `json:"id"`
 	Name ReleaseDateStatusType `json:"name"`
@@ -59,19 +120,107 @@ type GameType struct {
 	Type string `json:"type"`
 }
 
+type Company struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// InvolvedCompany is a company's role in making a game
+type InvolvedCompany struct {
+	Company   *Company `json:"company,omitempty"`
+	Developer bool     `json:"developer"`
+	Publisher bool     `json:"publisher"`
+}
+
+type Theme struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// GameMode is a way to play, e.g. "Single player" or "Co-operative"
+type GameMode struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// PlayerPerspective is a camera view, e.g. "First person"
+type PlayerPerspective struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Franchise is an IGDB franchise, e.g. "The Legend of Zelda"
+type Franchise struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Collection is an IGDB collection: a series of games, e.g. "The Witcher"
+type Collection struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
 type Game struct {
-	ID               int           `json:"id"`
-	AggregatedRating *float64      `json:"aggregated_rating,omitempty"`
-	Cover            *Cover        `json:"cover,omitempty"`
-	FirstReleaseDate *int64        `json:"first_release_date,omitempty"`
-	GameType         *GameType     `json:"game_type,omitempty"`
-	Genres           []Genre       `json:"genres,omitempty"`
-	Name             string        `json:"name"`
-	Platforms        []Platform    `json:"platforms,omitempty"`
-	ReleaseDates     []ReleaseDate `json:"release_dates,omitempty"`
-	UpdatedAt        *int64        `json:"updated_at,omitempty"`
-	URL              string        `json:"url,omitempty"`
-	Websites         []Website     `json:"websites,omitempty"`
+	ID                   int                 `json:"id"`
+	AggregatedRating     *float64            `json:"aggregated_rating,omitempty"`
+	Collections          []Collection        `json:"collections,omitempty"`
+	Cover                *Cover              `json:"cover,omitempty"`
+	DLCs                 []Game              `json:"dlcs,omitempty"`
+	Expansions           []Game              `json:"expansions,omitempty"`
+	FirstReleaseDate     *int64              `json:"first_release_date,omitempty"`
+	GameModes            []GameMode          `json:"game_modes,omitempty"`
+	Franchise            *Franchise          `json:"franchise,omitempty"` // The main franchise
+	Franchises           []Franchise         `json:"franchises,omitempty"`
+	GameType             *GameType           `json:"game_type,omitempty"`
+	Genres               []Genre             `json:"genres,omitempty"`
+	InvolvedCompanies    []InvolvedCompany   `json:"involved_companies,omitempty"`
+	Name                 string              `json:"name"`
+	ParentGame           *Game               `json:"parent_game,omitempty"` // Main game of a DLC, expansion or similar
+	Platforms            []Platform          `json:"platforms,omitempty"`
+	PlayerPerspectives   []PlayerPerspective `json:"player_perspectives,omitempty"`
+	ReleaseDates         []ReleaseDate       `json:"release_dates,omitempty"`
+	Remakes              []Game              `json:"remakes,omitempty"`
+	Remasters            []Game              `json:"remasters,omitempty"`
+	Screenshots          []Screenshot        `json:"screenshots,omitempty"`
+	StandaloneExpansions []Game              `json:"standalone_expansions,omitempty"`
+	Storyline            string              `json:"storyline,omitempty"`
+	Summary              string              `json:"summary,omitempty"`
+	Themes               []Theme             `json:"themes,omitempty"`
+	TimeToBeat           *TimeToBeat         `json:"-"` // From the game_time_to_beats endpoint (GetTimeToBeats); GetGameByID leaves it nil
+	UpdatedAt            *int64              `json:"updated_at,omitempty"`
+	URL                  string              `json:"url,omitempty"`
+	Videos               []Video             `json:"videos,omitempty"`
+	Websites             []Website           `json:"websites,omitempty"`
+}
+
+// TimeToBeat is IGDB's crowd-sourced playtime estimate for a game, in seconds; nil when
+// nobody has submitted that kind of playthrough
+type TimeToBeat struct {
+	ID         int  `json:"id"`
+	GameID     int  `json:"game_id"`
+	Hastily    *int `json:"hastily,omitempty"`    // Main story, rushed
+	Normally   *int `json:"normally,omitempty"`   // Main story and some extras
+	Completely *int `json:"completely,omitempty"` // Everything
+	Count      int  `json:"count,omitempty"`      // Submissions the estimates are based on
+}
+
+type ExternalGameSource int
+
+const (
+	ExternalGameSourceSteam ExternalGameSource = 1
+)
+
+type ExternalGame struct {
+	ID   int    `json:"id"`
+	UID  string `json:"uid"`
+	Game *Game  `json:"game,omitempty"`
+}
+
+type AlternativeName struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Game *Game  `json:"game,omitempty"`
 }
 
 type SearchResult struct {
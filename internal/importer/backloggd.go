@@ -0,0 +1,70 @@
+package importer
+
+import (
+	"io"
+	"strings"
+
+	"game-tracker/internal/model"
+)
+
+func init() {
+	RegisterSource(backloggdSource{})
+}
+
+// backloggdSource reads Backloggd's CSV export. Backloggd separates the list a game is
+// on (Played, Playing, Backlog, Wishlist) from how a played game ended (Completed,
+// Mastered, Retired, Shelved, Abandoned). Ratings are 0.5-5 stars.
+type backloggdSource struct{}
+
+func (backloggdSource) Name() string {
+	return "backloggd"
+}
+
+func (backloggdSource) Parse(r io.Reader) ([]Row, error) {
+	records, err := readCSVRecords(r, "Game Name", "Name", "Title", "Game")
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(records))
+	for _, record := range records {
+		b := newRowBuilder(record.line, record.get("Game Name", "Name", "Title", "Game"))
+
+		list := record.get("Status", "List")
+		playType := record.get("Play Type", "Played Status", "Play Status", "Completion")
+		status, ok := backloggdStatus(list, playType)
+		if !ok {
+			b.problem("unknown Backloggd status %q / %q", list, playType)
+		}
+		b.row.Status = status
+
+		b.datePlayed(record.get("Finished On", "Finish Date", "Date Finished", "Completed On", "Last Played"))
+		b.rating(record.get("Rating", "Your Rating", "Stars"), 5)
+
+		rows = append(rows, b.build())
+	}
+
+	return rows, nil
+}
+
+func backloggdStatus(list, playType string) (model.GameStatus, bool) {
+	switch strings.ToLower(playType) {
+	case "completed", "mastered", "retired":
+		return model.StatusDone, true
+	case "shelved":
+		return model.StatusBreak, true
+	case "abandoned":
+		return model.StatusAbandoned, true
+	}
+
+	switch strings.ToLower(list) {
+	case "played":
+		return model.StatusDone, true
+	case "playing":
+		return model.StatusPlaying, true
+	case "backlog", "wishlist", "":
+		return model.StatusBacklog, true
+	default:
+		return model.StatusBacklog, false
+	}
+}
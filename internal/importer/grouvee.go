@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"game-tracker/internal/model"
+)
+
+func init() {
+	RegisterSource(grouveeSource{})
+}
+
+// grouveeSource reads Grouvee's CSV export. The shelves column is a JSON object keyed by
+// shelf name and dates is a JSON array of play-throughs. Ratings are 1-5 stars.
+type grouveeSource struct{}
+
+func (grouveeSource) Name() string {
+	return "grouvee"
+}
+
+type grouveePlaythrough struct {
+	DateStarted  string `json:"date_started"`
+	DateFinished string `json:"date_finished"`
+}
+
+func (grouveeSource) Parse(r io.Reader) ([]Row, error) {
+	records, err := readCSVRecords(r, "name", "title")
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(records))
+	for _, record := range records {
+		b := newRowBuilder(record.line, record.get("name", "title"))
+
+		var shelves map[string]json.RawMessage
+		if raw := record.get("shelves"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &shelves); err != nil {
+				b.problem("invalid shelves value")
+			}
+		}
+		b.row.Status = grouveeStatus(shelves)
+
+		var playthroughs []grouveePlaythrough
+		if raw := record.get("dates"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &playthroughs); err != nil {
+				b.problem("invalid dates value")
+			}
+		}
+		b.datePlayed(latestFinished(playthroughs))
+
+		b.rating(record.get("rating"), 5)
+
+		rows = append(rows, b.build())
+	}
+
+	return rows, nil
+}
+
+// grouveeStatus maps default and common custom shelf names, most active shelf first
+func grouveeStatus(shelves map[string]json.RawMessage) model.GameStatus {
+	on := func(names ...string) bool {
+		for shelf := range shelves {
+			for _, name := range names {
+				if strings.EqualFold(shelf, name) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	switch {
+	case on("Playing"):
+		return model.StatusPlaying
+	case on("Played", "Beaten", "Completed", "Finished"):
+		return model.StatusDone
+	case on("Dropped", "Abandoned"):
+		return model.StatusAbandoned
+	case on("On Hold", "Paused"):
+		return model.StatusBreak
+	default:
+		return model.StatusBacklog
+	}
+}
+
+// latestFinished returns the most recent finish date (dates are ISO formatted, so they sort as strings)
+func latestFinished(playthroughs []grouveePlaythrough) string {
+	latest := ""
+	for _, p := range playthroughs {
+		if p.DateFinished > latest {
+			latest = p.DateFinished
+		}
+	}
+	return latest
+}
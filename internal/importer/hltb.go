@@ -0,0 +1,53 @@
+package importer
+
+import (
+	"io"
+
+	"game-tracker/internal/model"
+)
+
+func init() {
+	RegisterSource(hltbSource{})
+}
+
+// hltbSource reads HowLongToBeat's CSV export, where each list (Playing, Backlog, Replay,
+// Completed, Retired) is its own column marked for the games on it. Review scores are 0-100.
+type hltbSource struct{}
+
+func (hltbSource) Name() string {
+	return "hltb"
+}
+
+func (hltbSource) Parse(r io.Reader) ([]Row, error) {
+	records, err := readCSVRecords(r, "Title", "Game", "Name")
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(records))
+	for _, record := range records {
+		b := newRowBuilder(record.line, record.get("Title", "Game", "Name"))
+		b.row.Status = hltbStatus(record)
+
+		b.datePlayed(record.get("Completion Date", "Completed Date", "Date Completed", "Finished"))
+		b.rating(record.get("Review", "Review Score", "Rating"), 100)
+
+		rows = append(rows, b.build())
+	}
+
+	return rows, nil
+}
+
+// hltbStatus picks the most specific list a game is on. Retired is HLTB's "gave up" list.
+func hltbStatus(record csvRecord) model.GameStatus {
+	switch {
+	case record.has("Playing"), record.has("Replay"):
+		return model.StatusPlaying
+	case record.has("Completed"):
+		return model.StatusDone
+	case record.has("Retired"):
+		return model.StatusAbandoned
+	default:
+		return model.StatusBacklog
+	}
+}
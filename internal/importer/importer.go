@@ -0,0 +1,225 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"game-tracker/internal/database"
+	"game-tracker/internal/igdb"
+	"game-tracker/internal/matcher"
+	"game-tracker/internal/model"
+)
+
+// Importer matches import rows against IGDB and the user's library, then saves confirmed rows
+type Importer struct {
+	db         *database.Client
+	igdbClient *igdb.Client
+	matcher    *matcher.Matcher
+}
+
+// New creates an importer that matches rows with m, normally the matcher the background worker uses
+func New(db *database.Client, igdbClient *igdb.Client, m *matcher.Matcher) *Importer {
+	return &Importer{
+		db:         db,
+		igdbClient: igdbClient,
+		matcher:    m,
+	}
+}
+
+// Duplicate identifies the library game an import row would duplicate
+type Duplicate struct {
+	GameID string `json:"game_id,omitempty"` // Empty when the duplicate is another row in the same file
+	Title  string `json:"title"`
+	Line   int    `json:"line,omitempty"`
+}
+
+// PreviewRow is an import row annotated with its proposed IGDB match
+type PreviewRow struct {
+	Row
+	Match      *model.MatchCandidate  `json:"match,omitempty"`      // Proposed match, nil when none could be chosen
+	Candidates []model.MatchCandidate `json:"candidates,omitempty"` // Scored search results, best first, for picking another match
+	Duplicate  *Duplicate             `json:"duplicate,omitempty"`
+	Reason     string                 `json:"reason,omitempty"` // Why no match was proposed
+}
+
+// Preview is the result of matching an import file; nothing is saved until the user confirms it
+type Preview struct {
+	Rows       []PreviewRow `json:"rows"`
+	Total      int          `json:"total"`
+	Matched    int          `json:"matched"`
+	Unmatched  int          `json:"unmatched"`
+	Duplicates int          `json:"duplicates"`
+	Errors     int          `json:"errors"`
+}
+
+// Preview proposes an IGDB match for every row and flags duplicates of existing games
+func (im *Importer) Preview(ctx context.Context, userID string, rows []Row) (*Preview, error) {
+	preview := &Preview{
+		Rows:  make([]PreviewRow, 0, len(rows)),
+		Total: len(rows),
+	}
+
+	// IGDB IDs already claimed by earlier rows of the same file
+	seen := make(map[int]Row)
+
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		previewRow := PreviewRow{Row: row}
+
+		if row.Error != "" {
+			preview.Errors++
+			preview.Rows = append(preview.Rows, previewRow)
+			continue
+		}
+
+		igdbID := row.IGDBID
+		if igdbID == 0 {
+			result, err := im.matcher.Match(ctx, userID, "", row.Title)
+			if err != nil {
+				log.Printf("ERROR: Failed to match import row %d '%s': %v", row.Line, row.Title, err)
+				previewRow.Error = "IGDB search failed"
+				preview.Errors++
+				preview.Rows = append(preview.Rows, previewRow)
+				continue
+			}
+
+			previewRow.Candidates = result.Candidates
+			previewRow.Match = result.Match
+			previewRow.Reason = result.Reason
+			if result.Duplicate != nil {
+				previewRow.Duplicate = &Duplicate{GameID: result.Duplicate.ID, Title: result.Duplicate.Title}
+			}
+			if result.Match != nil {
+				igdbID = result.Match.IGDBID
+			}
+		}
+
+		if igdbID > 0 {
+			if previewRow.Duplicate == nil {
+				duplicate, err := im.findDuplicate(ctx, userID, igdbID, seen)
+				if err != nil {
+					return nil, err
+				}
+				previewRow.Duplicate = duplicate
+			}
+			seen[igdbID] = row
+		}
+
+		switch {
+		case previewRow.Duplicate != nil:
+			preview.Duplicates++
+		case igdbID > 0:
+			preview.Matched++
+		default:
+			preview.Unmatched++
+		}
+
+		preview.Rows = append(preview.Rows, previewRow)
+	}
+
+	return preview, nil
+}
+
+func (im *Importer) findDuplicate(ctx context.Context, userID string, igdbID int, seen map[int]Row) (*Duplicate, error) {
+	if previous, ok := seen[igdbID]; ok {
+		return &Duplicate{Title: previous.Title, Line: previous.Line}, nil
+	}
+
+	existingGame, err := im.db.GetGameByIGDBID(ctx, userID, igdbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicate game: %w", err)
+	}
+	if existingGame == nil {
+		return nil, nil
+	}
+
+	return &Duplicate{GameID: existingGame.ID, Title: existingGame.Title}, nil
+}
+
+// Result summarizes a committed import
+type Result struct {
+	Created []*model.Game `json:"created"`
+	Skipped []RowResult   `json:"skipped"`
+	Errors  []RowResult   `json:"errors"`
+}
+
+type RowResult struct {
+	Line   int    `json:"line"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// Commit saves confirmed rows as new games. Rows carrying an IGDB ID are skipped when the
+// user already owns that game. Metadata is left for the background worker to fill in,
+// the same as games created by the Notion migration.
+func (im *Importer) Commit(ctx context.Context, userID string, rows []Row) (*Result, error) {
+	result := &Result{
+		Created: []*model.Game{},
+		Skipped: []RowResult{},
+		Errors:  []RowResult{},
+	}
+
+	seen := make(map[int]Row)
+
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		row.Title = strings.TrimSpace(row.Title)
+		if row.Status == "" {
+			row.Status = model.StatusBacklog
+		}
+
+		if row.Error != "" {
+			result.Errors = append(result.Errors, RowResult{Line: row.Line, Title: row.Title, Reason: row.Error})
+			continue
+		}
+		if row.Title == "" {
+			result.Errors = append(result.Errors, RowResult{Line: row.Line, Reason: "missing title"})
+			continue
+		}
+		if !row.Status.IsValid() {
+			result.Errors = append(result.Errors, RowResult{Line: row.Line, Title: row.Title, Reason: fmt.Sprintf("invalid status %q", row.Status)})
+			continue
+		}
+
+		if row.IGDBID > 0 {
+			duplicate, err := im.findDuplicate(ctx, userID, row.IGDBID, seen)
+			if err != nil {
+				return nil, err
+			}
+			if duplicate != nil {
+				result.Skipped = append(result.Skipped, RowResult{Line: row.Line, Title: row.Title, Reason: fmt.Sprintf("already in library as '%s'", duplicate.Title)})
+				continue
+			}
+			seen[row.IGDBID] = row
+		}
+
+		game := &model.Game{
+			UserID:           userID,
+			Title:            row.Title,
+			IGDBID:           row.IGDBID,
+			Status:           row.Status,
+			DatePlayed:       row.DatePlayed,
+			UserRating:       row.UserRating,
+			ImportedSteamURL: row.SteamURL,
+		}
+
+		if err := im.db.SaveGame(ctx, game); err != nil {
+			log.Printf("ERROR: Failed to save imported game '%s': %v", row.Title, err)
+			result.Errors = append(result.Errors, RowResult{Line: row.Line, Title: row.Title, Reason: "failed to save game"})
+			continue
+		}
+
+		result.Created = append(result.Created, game)
+	}
+
+	log.Printf("Import complete for user %s: %d created, %d skipped, %d errors", userID, len(result.Created), len(result.Skipped), len(result.Errors))
+	return result, nil
+}
@@ -0,0 +1,292 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"game-tracker/internal/model"
+)
+
+// Row is a single game read from an import file, before any IGDB matching
+type Row struct {
+	Line       int              `json:"line"` // 1-based position in the source file (CSV line or JSON array index)
+	Title      string           `json:"title"`
+	Status     model.GameStatus `json:"status"`
+	DatePlayed *time.Time       `json:"date_played,omitempty"`
+	IGDBID     int              `json:"igdb_id,omitempty"`
+	SteamURL   string           `json:"steam_url,omitempty"`
+	UserRating int              `json:"user_rating,omitempty"` // 0-100
+	Error      string           `json:"error,omitempty"`       // Parse error; rows with errors are never saved
+}
+
+// ColumnMapping names the source columns (CSV headers or JSON keys) holding each field.
+// Empty entries are detected from the header using common aliases.
+type ColumnMapping struct {
+	Title      string `json:"title,omitempty"`
+	Status     string `json:"status,omitempty"`
+	DatePlayed string `json:"date_played,omitempty"`
+	IGDBID     string `json:"igdb_id,omitempty"`
+	UserRating string `json:"user_rating,omitempty"` // Values on a 0-100 scale
+}
+
+var columnAliases = map[string][]string{
+	"title":       {"title", "name", "game", "game title", "game name"},
+	"status":      {"status", "state", "list"},
+	"date_played": {"date_played", "date played", "played", "played date", "completed", "completed date", "finished", "finished date", "date completed"},
+	"igdb_id":     {"igdb_id", "igdb id", "igdb"},
+	"user_rating": {"user_rating", "user rating", "my rating", "personal rating"},
+}
+
+// resolve fills empty mapping entries from the available column names
+func (m ColumnMapping) resolve(columns []string) ColumnMapping {
+	find := func(current, field string) string {
+		if current != "" {
+			return current
+		}
+		for _, alias := range columnAliases[field] {
+			for _, column := range columns {
+				if strings.EqualFold(strings.TrimSpace(column), alias) {
+					return column
+				}
+			}
+		}
+		return ""
+	}
+
+	return ColumnMapping{
+		Title:      find(m.Title, "title"),
+		Status:     find(m.Status, "status"),
+		DatePlayed: find(m.DatePlayed, "date_played"),
+		IGDBID:     find(m.IGDBID, "igdb_id"),
+		UserRating: find(m.UserRating, "user_rating"),
+	}
+}
+
+// ParseCSV reads rows from a CSV file with a header line
+func ParseCSV(r io.Reader, mapping ColumnMapping) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	mapping = mapping.resolve(header)
+	if mapping.Title == "" {
+		return nil, fmt.Errorf("no title column found in CSV header")
+	}
+
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[column] = i
+	}
+	for _, column := range []string{mapping.Title, mapping.Status, mapping.DatePlayed, mapping.IGDBID, mapping.UserRating} {
+		if _, ok := index[column]; column != "" && !ok {
+			return nil, fmt.Errorf("column %q not found in CSV header", column)
+		}
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		get := func(column string) string {
+			i, ok := index[column]
+			if column == "" || !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		rows = append(rows, buildRow(line, get, mapping))
+	}
+
+	return rows, nil
+}
+
+// ParseJSON reads rows from a JSON array of objects
+func ParseJSON(r io.Reader, mapping ColumnMapping) ([]Row, error) {
+	var records []map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	keys := make(map[string]struct{})
+	for _, record := range records {
+		for key := range record {
+			keys[key] = struct{}{}
+		}
+	}
+	columns := make([]string, 0, len(keys))
+	for key := range keys {
+		columns = append(columns, key)
+	}
+
+	mapping = mapping.resolve(columns)
+	if mapping.Title == "" {
+		return nil, fmt.Errorf("no title field found in JSON records")
+	}
+
+	rows := make([]Row, 0, len(records))
+	for i, record := range records {
+		get := func(key string) string {
+			if key == "" {
+				return ""
+			}
+			return stringValue(record[key])
+		}
+		rows = append(rows, buildRow(i+1, get, mapping))
+	}
+
+	return rows, nil
+}
+
+// buildRow reads the mapped fields of one record through get
+func buildRow(line int, get func(column string) string, mapping ColumnMapping) Row {
+	row := Row{
+		Line:   line,
+		Title:  strings.TrimSpace(get(mapping.Title)),
+		Status: model.StatusBacklog,
+	}
+
+	var problems []string
+
+	if row.Title == "" {
+		problems = append(problems, "missing title")
+	}
+
+	if s := strings.TrimSpace(get(mapping.Status)); s != "" {
+		parsed, ok := ParseStatus(s)
+		if ok {
+			row.Status = parsed
+		} else {
+			problems = append(problems, fmt.Sprintf("unknown status %q", s))
+		}
+	}
+
+	if d := strings.TrimSpace(get(mapping.DatePlayed)); d != "" {
+		parsed, err := ParseDate(d)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else {
+			row.DatePlayed = parsed
+		}
+	}
+
+	if id := strings.TrimPrefix(strings.TrimSpace(get(mapping.IGDBID)), ":"); id != "" {
+		parsed, err := strconv.Atoi(id)
+		if err != nil || parsed < 0 {
+			problems = append(problems, fmt.Sprintf("invalid IGDB ID %q", id))
+		} else {
+			row.IGDBID = parsed
+		}
+	}
+
+	if rating := strings.TrimSpace(get(mapping.UserRating)); rating != "" {
+		parsed, err := ParseRating(rating, 100)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else {
+			row.UserRating = parsed
+		}
+	}
+
+	row.Error = strings.Join(problems, "; ")
+	return row
+}
+
+// ParseRating converts a rating on a 0-scale range to the 0-100 range used by model.Game
+func ParseRating(s string, scale float64) (int, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || value < 0 || value > scale {
+		return 0, fmt.Errorf("invalid rating %q", s)
+	}
+	return int(math.Round(value / scale * 100)), nil
+}
+
+// ParseStatus maps a status label onto model.GameStatus, ignoring case and punctuation
+func ParseStatus(s string) (model.GameStatus, bool) {
+	normalized := strings.ToLower(strings.NewReplacer("'", "", "’", "", "_", " ", "-", " ").Replace(strings.TrimSpace(s)))
+	switch normalized {
+	case "backlog":
+		return model.StatusBacklog, true
+	case "break", "on hold", "paused":
+		return model.StatusBreak, true
+	case "playing", "in progress":
+		return model.StatusPlaying, true
+	case "done", "completed", "finished", "beaten":
+		return model.StatusDone, true
+	case "abandoned", "dropped":
+		return model.StatusAbandoned, true
+	case "wont play", "skipped":
+		return model.StatusWontPlay, true
+	default:
+		return "", false
+	}
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+	"01/02/2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+// ParseDate accepts the date formats commonly produced by spreadsheets and other trackers
+func ParseDate(s string) (*time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognized date %q", s)
+}
+
+func stringValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
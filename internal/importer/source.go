@@ -0,0 +1,165 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Source converts another service's export file into import rows. Rows then go through
+// the same Preview/Commit pipeline as generic CSV and JSON imports.
+type Source interface {
+	// Name is the value of the import format parameter selecting this source
+	Name() string
+	Parse(r io.Reader) ([]Row, error)
+}
+
+var sources = map[string]Source{}
+
+// RegisterSource makes a source available by name; sources register themselves in init
+func RegisterSource(source Source) {
+	sources[source.Name()] = source
+}
+
+// LookupSource returns the registered source with the given name
+func LookupSource(name string) (Source, bool) {
+	source, ok := sources[strings.ToLower(name)]
+	return source, ok
+}
+
+// SourceNames lists the registered sources in alphabetical order
+func SourceNames() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// csvRecord is one CSV line keyed by case-insensitive header name
+type csvRecord struct {
+	line   int
+	values map[string]string
+}
+
+// get returns the first non-empty value among the given column names
+func (r csvRecord) get(columns ...string) string {
+	for _, column := range columns {
+		if value := strings.TrimSpace(r.values[strings.ToLower(column)]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// has reports whether the column holds a truthy marker ("x", "yes", "true", "1")
+func (r csvRecord) has(column string) bool {
+	switch strings.ToLower(r.get(column)) {
+	case "x", "yes", "y", "true", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// readCSVRecords reads a headed CSV file, requiring at least one of titleColumns
+func readCSVRecords(r io.Reader, titleColumns ...string) ([]csvRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	found := false
+	for _, column := range header {
+		for _, title := range titleColumns {
+			if column == strings.ToLower(title) {
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("CSV header has none of the expected title columns %v", titleColumns)
+	}
+
+	var records []csvRecord
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if isBlank(fields) {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		record := csvRecord{line: line, values: make(map[string]string, len(header))}
+		for i, column := range header {
+			if i < len(fields) {
+				record.values[column] = fields[i]
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// rowBuilder assembles a row from a service export, collecting problems into the row error
+type rowBuilder struct {
+	row      Row
+	problems []string
+}
+
+func newRowBuilder(line int, title string) *rowBuilder {
+	b := &rowBuilder{row: Row{Line: line, Title: strings.TrimSpace(title)}}
+	if b.row.Title == "" {
+		b.problem("missing title")
+	}
+	return b
+}
+
+func (b *rowBuilder) problem(format string, args ...interface{}) {
+	b.problems = append(b.problems, fmt.Sprintf(format, args...))
+}
+
+func (b *rowBuilder) datePlayed(value string) {
+	if value == "" {
+		return
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		b.problem("%v", err)
+		return
+	}
+	b.row.DatePlayed = parsed
+}
+
+func (b *rowBuilder) rating(value string, scale float64) {
+	if value == "" {
+		return
+	}
+	parsed, err := ParseRating(value, scale)
+	if err != nil {
+		b.problem("%v", err)
+		return
+	}
+	b.row.UserRating = parsed
+}
+
+func (b *rowBuilder) build() Row {
+	b.row.Error = strings.Join(b.problems, "; ")
+	return b.row
+}
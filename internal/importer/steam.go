@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"game-tracker/internal/model"
+)
+
+// SteamApp is one entry of a Steam library
+type SteamApp struct {
+	AppID int    `json:"appid"`
+	Name  string `json:"name,omitempty"`
+}
+
+// SteamStoreURL returns the store page for the app
+func (a SteamApp) SteamStoreURL() string {
+	return fmt.Sprintf("https://store.steampowered.com/app/%d/", a.AppID)
+}
+
+// ownedGamesResponse is the IPlayerService/GetOwnedGames response body
+type ownedGamesResponse struct {
+	Response struct {
+		Games []SteamApp `json:"games"`
+	} `json:"response"`
+}
+
+// ParseSteamLibrary reads a Steam library from either a GetOwnedGames JSON response
+// (the full response, its inner "response" object, or a bare games array) or a plain
+// list of app IDs separated by newlines, commas or whitespace. Lines starting with # are ignored.
+func ParseSteamLibrary(r io.Reader) ([]SteamApp, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Steam library: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("steam library is empty")
+	}
+
+	var apps []SteamApp
+	switch trimmed[0] {
+	case '{':
+		apps, err = parseOwnedGamesObject(trimmed)
+	case '[':
+		err = json.Unmarshal(trimmed, &apps)
+	default:
+		apps, err = parseAppIDList(trimmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return dedupeSteamApps(apps), nil
+}
+
+func parseOwnedGamesObject(data []byte) ([]SteamApp, error) {
+	var full ownedGamesResponse
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, fmt.Errorf("failed to decode Steam library JSON: %w", err)
+	}
+	if len(full.Response.Games) > 0 {
+		return full.Response.Games, nil
+	}
+
+	// Inner "response" object saved on its own
+	var inner struct {
+		Games []SteamApp `json:"games"`
+	}
+	if err := json.Unmarshal(data, &inner); err != nil {
+		return nil, fmt.Errorf("failed to decode Steam library JSON: %w", err)
+	}
+	if len(inner.Games) == 0 {
+		return nil, fmt.Errorf("no games found in Steam library JSON")
+	}
+	return inner.Games, nil
+}
+
+func parseAppIDList(data []byte) ([]SteamApp, error) {
+	var apps []SteamApp
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ';' || r == ' ' || r == '\t' }) {
+			appID, err := strconv.Atoi(field)
+			if err != nil || appID <= 0 {
+				return nil, fmt.Errorf("invalid Steam app ID %q on line %d", field, line)
+			}
+			apps = append(apps, SteamApp{AppID: appID})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Steam app IDs: %w", err)
+	}
+
+	return apps, nil
+}
+
+func dedupeSteamApps(apps []SteamApp) []SteamApp {
+	seen := make(map[int]bool, len(apps))
+	out := make([]SteamApp, 0, len(apps))
+	for _, app := range apps {
+		if app.AppID <= 0 || seen[app.AppID] {
+			continue
+		}
+		seen[app.AppID] = true
+		out = append(out, app)
+	}
+	return out
+}
+
+// SteamRows maps Steam apps to Backlog import rows through IGDB's external_games.
+// Apps IGDB doesn't know keep their Steam name so Preview can fall back to a title search.
+func (im *Importer) SteamRows(ctx context.Context, apps []SteamApp) ([]Row, error) {
+	appIDs := make([]int, len(apps))
+	for i, app := range apps {
+		appIDs[i] = app.AppID
+	}
+
+	igdbGames, err := im.igdbClient.GetGamesBySteamAppIDs(ctx, appIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(apps))
+	for i, app := range apps {
+		row := Row{
+			Line:     i + 1,
+			Title:    app.Name,
+			Status:   model.StatusBacklog,
+			SteamURL: app.SteamStoreURL(),
+		}
+
+		if igdbGame, ok := igdbGames[app.AppID]; ok {
+			row.IGDBID = igdbGame.ID
+			if igdbGame.Name != "" {
+				row.Title = igdbGame.Name
+			}
+		} else if row.Title == "" {
+			row.Title = fmt.Sprintf("Steam app %d", app.AppID)
+			row.Error = fmt.Sprintf("Steam app %d is not linked to an IGDB game", app.AppID)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,375 @@
+package matcher
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"game-tracker/internal/database"
+	"game-tracker/internal/igdb"
+	"game-tracker/internal/model"
+)
+
+// DefaultThreshold is the confidence a candidate needs to be matched automatically
+const DefaultThreshold = 0.85
+
+// minMargin is how far the best candidate must lead the runner-up; closer scores are
+// left for the user to choose
+const minMargin = 0.1
+
+// SearchFunc looks up IGDB candidates for a title (igdb.Client.Search, optionally behind the search cache)
+type SearchFunc func(ctx context.Context, query string) ([]igdb.SearchCandidate, error)
+
+// Matcher finds the IGDB game for a title the way the background worker does, so imports
+// and automatic matching agree on what counts as a match
+type Matcher struct {
+	db             *database.Client
+	search         SearchFunc
+	searchAltNames SearchFunc
+	threshold      float64
+}
+
+// New creates a matcher; a threshold of zero uses DefaultThreshold. searchAltNames
+// (igdb.Client.SearchAlternativeNames) may be nil to search titles only.
+func New(db *database.Client, search, searchAltNames SearchFunc, threshold float64) *Matcher {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+
+	return &Matcher{
+		db:             db,
+		search:         search,
+		searchAltNames: searchAltNames,
+		threshold:      threshold,
+	}
+}
+
+// Result describes the outcome of matching one title
+type Result struct {
+	Status     model.MatchStatus      // matched, multiple, no_match or needs_review
+	Match      *model.MatchCandidate  // Chosen candidate (also set for needs_review)
+	Candidates []model.MatchCandidate // Scored candidates, best first
+	Duplicate  *model.Game            // Library game already using the chosen IGDB ID (needs_review)
+	Reason     string                 // Why no game was matched; empty when matched
+}
+
+// storedCandidates is how many candidates are kept on a game for review
+const storedCandidates = 5
+
+// TopCandidates returns the best candidates worth storing on the game
+func (r *Result) TopCandidates() []model.MatchCandidate {
+	return r.Candidates[:min(len(r.Candidates), storedCandidates)]
+}
+
+// lookup is one IGDB query tried while matching
+type lookup struct {
+	search SearchFunc
+	query  string
+}
+
+// Match searches IGDB for title and picks the best-scoring candidate when it is confident
+// enough and clearly ahead of the others. When the title itself finds no confident match,
+// it also searches IGDB's alternative names and simplified variants of the title (see
+// Variants), stopping at the first confident match. A chosen candidate already used by
+// another of the user's games (other than gameID) yields needs_review instead of matched.
+func (m *Matcher) Match(ctx context.Context, userID, gameID, title string) (*Result, error) {
+	query, year := SplitYear(title)
+	variants := Variants(query)
+
+	lookups := []lookup{{m.search, variants[0].Query}}
+	if m.searchAltNames != nil {
+		lookups = append(lookups, lookup{m.searchAltNames, variants[0].Query})
+	}
+	for _, variant := range variants[1:] {
+		lookups = append(lookups, lookup{m.search, variant.Query})
+	}
+
+	result := &Result{}
+	var results []igdb.SearchCandidate
+	for _, l := range lookups {
+		found, err := l.search(ctx, l.query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search IGDB for '%s': %w", l.query, err)
+		}
+		results = append(results, found...)
+
+		result.Candidates = Rank(variants, year, results)
+		if len(result.Candidates) > 0 {
+			if result.Match, result.Reason = m.pick(result.Candidates); result.Match != nil {
+				break
+			}
+		}
+	}
+
+	if len(result.Candidates) == 0 {
+		result.Status = model.MatchStatusNoMatch
+		result.Reason = fmt.Sprintf("IGDB returned no games for '%s'", query)
+		if len(variants) > 1 || m.searchAltNames != nil {
+			result.Reason += ", its alternative names or simplified titles"
+		}
+		return result, nil
+	}
+
+	if result.Match == nil {
+		result.Status = model.MatchStatusMultiple
+		return result, nil
+	}
+
+	existingGame, err := m.db.GetGameByIGDBID(ctx, userID, result.Match.IGDBID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicate IGDB ID %d: %w", result.Match.IGDBID, err)
+	}
+
+	if existingGame != nil && existingGame.ID != gameID {
+		result.Status = model.MatchStatusNeedsReview
+		result.Duplicate = existingGame
+		result.Reason = fmt.Sprintf("Best match '%s' is already in your library as '%s'", result.Match.Name, existingGame.Title)
+		return result, nil
+	}
+
+	result.Status = model.MatchStatusMatched
+	return result, nil
+}
+
+// pick returns the top candidate if it clears the threshold and leads the runner-up by
+// minMargin, or else the reason it doesn't
+func (m *Matcher) pick(ranked []model.MatchCandidate) (*model.MatchCandidate, string) {
+	best := &ranked[0]
+	if best.Score < m.threshold {
+		return nil, fmt.Sprintf("Best match '%s' scored %.2f, below the %.2f needed to match automatically", best.Name, best.Score, m.threshold)
+	}
+	if len(ranked) > 1 && best.Score-ranked[1].Score < minMargin {
+		return nil, fmt.Sprintf("'%s' (%.2f) and '%s' (%.2f) are too close to choose between", best.Name, best.Score, ranked[1].Name, ranked[1].Score)
+	}
+	return best, ""
+}
+
+// excludedGameTypes are never matched automatically: they duplicate or repackage another game
+var excludedGameTypes = map[string]bool{
+	"Bundle": true,
+	"Pack":   true,
+	"Port":   true,
+	"Fork":   true,
+	"Mod":    true,
+	"Update": true,
+}
+
+// secondaryGameTypes only win when their name matches exactly, so "Game" prefers the
+// main game over "Game: Some DLC"
+var secondaryGameTypes = map[string]bool{
+	"DLC":       true,
+	"Expansion": true,
+	"Episode":   true,
+	"Season":    true,
+}
+
+// Rank scores search results against the variants of a title (and optional release year
+// hint), drops excluded game types and duplicates, and returns the rest best first
+func Rank(variants []Variant, year int, results []igdb.SearchCandidate) []model.MatchCandidate {
+	ranked := make([]model.MatchCandidate, 0, len(results))
+	index := make(map[int]int, len(results))
+	for _, result := range results {
+		if excludedGameTypes[result.GameType] {
+			continue
+		}
+
+		score := 0.0
+		for _, variant := range variants {
+			score = max(score, Score(variant.Query, year, result)-variant.Penalty)
+		}
+
+		// The same game can come back from several lookups; keep its best score
+		if i, ok := index[result.ID]; ok {
+			ranked[i].Score = max(ranked[i].Score, score)
+			continue
+		}
+		index[result.ID] = len(ranked)
+		ranked = append(ranked, model.MatchCandidate{
+			IGDBID:      result.ID,
+			Name:        result.Name,
+			CoverURL:    result.CoverURL,
+			ReleaseYear: result.ReleaseYear,
+			GameType:    result.GameType,
+			Score:       score,
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
+
+// Score rates how likely a search result is the game called title, from 0 to 1: title
+// similarity, a bonus for an exact (normalized) match, and the release year when known.
+// Results found through an alternative name are compared with that name as well.
+func Score(title string, year int, result igdb.SearchCandidate) float64 {
+	query := NormalizeTitle(title)
+
+	score, exact := nameScore(title, query, result.Name)
+	if result.MatchedName != "" {
+		altScore, altExact := nameScore(title, query, result.MatchedName)
+		score, exact = max(score, altScore), exact || altExact
+	}
+	if !exact && secondaryGameTypes[result.GameType] {
+		score -= 0.1
+	}
+
+	if year > 0 && result.ReleaseYear > 0 {
+		switch diff := year - result.ReleaseYear; {
+		case diff == 0:
+			score += 0.1
+		case diff < -1 || diff > 1:
+			score -= 0.15
+		}
+	}
+
+	return min(max(score, 0), 1)
+}
+
+// nameScore is the title similarity part of Score and whether the names match exactly
+// once normalized
+func nameScore(title, query, name string) (float64, bool) {
+	normalized := NormalizeTitle(name)
+	score := 0.8 * max(editSimilarity(query, normalized), wordSimilarity(title, name))
+	if query == normalized {
+		return score + 0.2, true
+	}
+	return score, false
+}
+
+// Variant is a query tried for a title, with the score penalty for matching it rather
+// than the full title
+type Variant struct {
+	Query   string
+	Penalty float64
+}
+
+// Matching a shortened title is weaker evidence. Without a subtitle, a title mostly
+// widens the search: "Batman: Arkham City" must still beat plain "Batman" on the full
+// title, and matching the short title alone stays below the default threshold.
+const (
+	editionPenalty  = 0.05 // "Game - Deluxe Edition" matching "Game"
+	subtitlePenalty = 0.2  // "Game: Subtitle" matching "Game"
+)
+
+// trademarks are dropped from every query; IGDB titles rarely carry them
+var trademarks = strings.NewReplacer("™", "", "®", "", "©", "")
+
+// editionSuffix matches store edition names at the end of a title, e.g.
+// "Game - Game of the Year Edition", "Game (Deluxe Edition)" or "Game GOTY"
+var editionSuffix = regexp.MustCompile(`(?i)[\s:\-–—(\[]*\b(?:(?:the\s+)?(?:game\s+of\s+the\s+year|goty|deluxe|digital\s+deluxe|definitive|complete|ultimate|gold|premium|special|standard|enhanced|anniversary|collector'?s|legendary)\s+edition|goty|director'?s\s+cut)[)\]]?\s*$`)
+
+// subtitleSeparator splits a title from its subtitle, e.g. "Game: Subtitle" or "Game - Subtitle"
+var subtitleSeparator = regexp.MustCompile(`\s*(?::|\s[-–—]\s)`)
+
+// Variants returns the queries to try for a title, most specific first: the title
+// without trademark symbols, then without an edition suffix, then without a subtitle.
+// The first variant is always the title itself.
+func Variants(title string) []Variant {
+	base := strings.Join(strings.Fields(trademarks.Replace(title)), " ")
+	variants := []Variant{{Query: base}}
+	seen := map[string]bool{NormalizeTitle(base): true}
+
+	add := func(query string, penalty float64) {
+		query = strings.TrimSpace(query)
+		key := NormalizeTitle(query)
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		variants = append(variants, Variant{Query: query, Penalty: penalty})
+	}
+
+	withoutEdition := editionSuffix.ReplaceAllString(base, "")
+	add(withoutEdition, editionPenalty)
+
+	if loc := subtitleSeparator.FindStringIndex(withoutEdition); loc != nil {
+		add(withoutEdition[:loc[0]], subtitlePenalty)
+	}
+
+	return variants
+}
+
+// trailingYear matches a release year hint at the end of a title, e.g. "Doom (2016)"
+var trailingYear = regexp.MustCompile(`\s*[\(\[]((?:19|20)\d{2})[\)\]]\s*$`)
+
+// SplitYear separates a trailing "(YYYY)" release year hint from a title
+func SplitYear(title string) (string, int) {
+	match := trailingYear.FindStringSubmatchIndex(title)
+	if match == nil {
+		return title, 0
+	}
+	year, _ := strconv.Atoi(title[match[2]:match[3]])
+	return title[:match[0]], year
+}
+
+// NormalizeTitle lowercases a title and drops everything but letters and digits
+func NormalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127 {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// editSimilarity is 1 minus the edit distance relative to the longer string
+func editSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+// wordSimilarity is the Dice coefficient of the titles' word sets, which tolerates
+// reordered or missing words better than edit distance
+func wordSimilarity(a, b string) float64 {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(wordsA)+len(wordsB))
+}
+
+func words(title string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		set[word] = true
+	}
+	return set
+}
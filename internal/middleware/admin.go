@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// AdminMiddleware only lets the listed users through. It must run after AuthMiddleware;
+// with an empty list every request is forbidden.
+func AdminMiddleware(adminUserIDs []string) func(http.Handler) http.Handler {
+	admins := make(map[string]bool, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserIDFromContext(r.Context())
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			if !admins[userID] {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -35,22 +35,111 @@ const (
 	MatchStatusNeedsReview MatchStatus = "needs_review" // User needs to review/fix match
 )
 
+// Sentinel dates are stored in place of missing dates so Firestore ordering puts
+// those games last. They carry no meaning outside the database.
+var (
+	ReleaseDateSentinel = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	DatePlayedSentinel  = time.Unix(0, 0)
+)
+
+// SteamLink returns the game's Steam store page: IGDB's, or the imported one when IGDB
+// has none
+func (g *Game) SteamLink() string {
+	if g.SteamURL != "" {
+		return g.SteamURL
+	}
+	return g.ImportedSteamURL
+}
+
+// HasReleaseDate reports whether the game has a real (non-sentinel) release date
+func (g *Game) HasReleaseDate() bool {
+	return g.ReleaseDate != nil && !g.ReleaseDate.Equal(ReleaseDateSentinel)
+}
+
+// HasDatePlayed reports whether the game has a real (non-sentinel) played date
+func (g *Game) HasDatePlayed() bool {
+	return g.DatePlayed != nil && !g.DatePlayed.Equal(DatePlayedSentinel)
+}
+
+// MatchTitle is the text the matcher searches IGDB for: the search hint if set, else the title
+func (g *Game) MatchTitle() string {
+	if g.SearchHint != "" {
+		return g.SearchHint
+	}
+	return g.Title
+}
+
 type Game struct {
-	ID            string      `firestore:"id" json:"id"`
-	UserID        string      `firestore:"user_id" json:"user_id"`
-	Title         string      `firestore:"title" json:"title"`
-	IGDBID        int         `firestore:"igdb_id" json:"igdb_id"` // 0 means no IGDB ID (unmatched)
-	CoverURL      string      `firestore:"cover_url,omitempty" json:"cover_url,omitempty"`
-	Rating        int         `firestore:"rating,omitempty" json:"rating,omitempty"` // 0-100
-	Status        GameStatus  `firestore:"status" json:"status"`
-	Genres        []string    `firestore:"genres,omitempty" json:"genres,omitempty"`
-	Platforms     []string    `firestore:"platforms,omitempty" json:"platforms,omitempty"`
-	ReleaseDate   *time.Time  `firestore:"release_date,omitempty" json:"release_date,omitempty"`
-	DatePlayed    *time.Time  `firestore:"date_played,omitempty" json:"date_played,omitempty"`
-	SteamURL      string      `firestore:"steam_url,omitempty" json:"steam_url,omitempty"`
-	OfficialURL   string      `firestore:"official_url,omitempty" json:"official_url,omitempty"`
-	MatchStatus   MatchStatus `firestore:"match_status,omitempty" json:"match_status,omitempty"`
-	CreatedAt     time.Time   `firestore:"created_at" json:"created_at"`
-	UpdatedAt     time.Time   `firestore:"updated_at" json:"updated_at"`
-	LastSyncError string      `firestore:"last_sync_error,omitempty" json:"last_sync_error,omitempty"`
+	ID                 string            `firestore:"id" json:"id"`
+	UserID             string            `firestore:"user_id" json:"user_id"`
+	Title              string            `firestore:"title" json:"title"`
+	IGDBID             int               `firestore:"igdb_id" json:"igdb_id"` // 0 means no IGDB ID (unmatched)
+	CoverURL           string            `firestore:"cover_url,omitempty" json:"cover_url,omitempty"`
+	Rating             int               `firestore:"rating,omitempty" json:"rating,omitempty"`           // 0-100
+	UserRating         int               `firestore:"user_rating,omitempty" json:"user_rating,omitempty"` // 0-100, the user's own rating
+	Status             GameStatus        `firestore:"status" json:"status"`
+	Genres             []string          `firestore:"genres,omitempty" json:"genres,omitempty"`
+	Platforms          []string          `firestore:"platforms,omitempty" json:"platforms,omitempty"`
+	ReleaseDate        *time.Time        `firestore:"release_date,omitempty" json:"release_date,omitempty"`
+	ReleasePrecision   DatePrecision     `firestore:"release_precision,omitempty" json:"release_precision,omitempty"` // How exactly ReleaseDate is known; empty means day
+	ReleaseLabel       string            `firestore:"release_label,omitempty" json:"release_label,omitempty"`         // Human-readable imprecise release date, e.g. "Q3 2025"
+	DatePlayed         *time.Time        `firestore:"date_played,omitempty" json:"date_played,omitempty"`
+	SteamURL           string            `firestore:"steam_url,omitempty" json:"steam_url,omitempty"`                   // From IGDB, which stays authoritative
+	ImportedSteamURL   string            `firestore:"imported_steam_url,omitempty" json:"imported_steam_url,omitempty"` // Store page of the Steam app the game was imported from
+	OfficialURL        string            `firestore:"official_url,omitempty" json:"official_url,omitempty"`
+	IGDBURL            string            `firestore:"igdb_url,omitempty" json:"igdb_url,omitempty"`
+	MatchStatus        MatchStatus       `firestore:"match_status,omitempty" json:"match_status,omitempty"`
+	CreatedAt          time.Time         `firestore:"created_at" json:"created_at"`
+	UpdatedAt          time.Time         `firestore:"updated_at" json:"updated_at"`
+	LastSyncError      string            `firestore:"last_sync_error,omitempty" json:"last_sync_error,omitempty"`
+	NextSyncAt         *time.Time        `firestore:"next_sync_at,omitempty" json:"next_sync_at,omitempty"`             // When the background sync next refreshes the game; nil for unmatched games and games whose sync is disabled
+	SyncFailures       int               `firestore:"sync_failures,omitempty" json:"sync_failures,omitempty"`           // Consecutive failed syncs, reset on success
+	SyncDisabled       bool              `firestore:"sync_disabled,omitempty" json:"sync_disabled,omitempty"`           // Set after too many consecutive failures; cleared by re-matching
+	MatchCandidates    []MatchCandidate  `firestore:"match_candidates,omitempty" json:"match_candidates,omitempty"`     // Ranked candidates from the last automatic match
+	MatchReason        string            `firestore:"match_reason,omitempty" json:"match_reason,omitempty"`             // Why the last automatic match didn't pick a game
+	SearchHint         string            `firestore:"search_hint,omitempty" json:"search_hint,omitempty"`               // User-supplied IGDB search text, used instead of the title when matching
+	MatchAttempts      int               `firestore:"match_attempts,omitempty" json:"match_attempts,omitempty"`         // Automatic match attempts that found no single match, reset by a match or a new hint
+	NextMatchAt        *time.Time        `firestore:"next_match_at,omitempty" json:"next_match_at,omitempty"`           // When the background sync next tries to match the game; nil means due now
+	Releases           []PlatformRelease `firestore:"releases,omitempty" json:"releases,omitempty"`                     // Per-platform, per-region releases from IGDB, earliest first
+	PreferredPlatform  string            `firestore:"preferred_platform,omitempty" json:"preferred_platform,omitempty"` // Platform the user plans to play on; overrides the settings default
+	TimeToBeat         *TimeToBeat       `firestore:"time_to_beat,omitempty" json:"time_to_beat,omitempty"`             // IGDB playtime estimates; nil when IGDB has none
+	GameType           string            `firestore:"game_type,omitempty" json:"game_type,omitempty"`                   // IGDB game type, e.g. "Main Game", "DLC" or "Remaster"
+	ParentGame         *GameRef          `firestore:"parent_game,omitempty" json:"parent_game,omitempty"`               // Main game of a DLC, expansion, remaster or similar
+	Franchises         []string          `firestore:"franchises,omitempty" json:"franchises,omitempty"`                 // Main franchise first
+	Collections        []string          `firestore:"collections,omitempty" json:"collections,omitempty"`               // Series the game belongs to
+	DLCs               []GameRef         `firestore:"dlcs,omitempty" json:"dlcs,omitempty"`                             // DLC released for the game
+	Expansions         []GameRef         `firestore:"expansions,omitempty" json:"expansions,omitempty"`                 // Expansions, standalone ones included
+	Remasters          []GameRef         `firestore:"remasters,omitempty" json:"remasters,omitempty"`                   // Remasters and remakes of the game
+	Developers         []string          `firestore:"developers,omitempty" json:"developers,omitempty"`
+	Publishers         []string          `firestore:"publishers,omitempty" json:"publishers,omitempty"`
+	Summary            string            `firestore:"summary,omitempty" json:"summary,omitempty"`
+	Storyline          string            `firestore:"storyline,omitempty" json:"storyline,omitempty"`
+	Themes             []string          `firestore:"themes,omitempty" json:"themes,omitempty"`                           // e.g. "Fantasy" or "Horror"
+	GameModes          []string          `firestore:"game_modes,omitempty" json:"game_modes,omitempty"`                   // e.g. "Single player" or "Co-operative"
+	PlayerPerspectives []string          `firestore:"player_perspectives,omitempty" json:"player_perspectives,omitempty"` // e.g. "First person"
+	Screenshots        []string          `firestore:"screenshots,omitempty" json:"screenshots,omitempty"`                 // Screenshot URLs, at most MaxScreenshots
+	Videos             []GameVideo       `firestore:"videos,omitempty" json:"videos,omitempty"`                           // Trailers and other videos, at most MaxVideos
+
+	// ReleaseDays lists the days (ReleaseDayKey) the game has an exact release on, across
+	// its first release and per-platform releases, so release day checks can query by day.
+	// The database keeps it in step with the releases; it is never served.
+	ReleaseDays []string `firestore:"release_days,omitempty" json:"-"`
+
+	// PlatformRelease is ReleaseFor the requesting user's default platform, filled in by
+	// list endpoints and never stored
+	PlatformRelease *PlatformRelease `firestore:"-" json:"platform_release,omitempty"`
+
+	// Relation is ParentRelation with the parent looked up in the user's library, filled
+	// in by the API and never stored
+	Relation *GameRelation `firestore:"-" json:"relation,omitempty"`
+}
+
+// MatchCandidate is a scored IGDB search result kept on a game for match review
+type MatchCandidate struct {
+	IGDBID      int     `firestore:"igdb_id" json:"igdb_id"`
+	Name        string  `firestore:"name" json:"name"`
+	CoverURL    string  `firestore:"cover_url,omitempty" json:"cover_url,omitempty"`
+	ReleaseYear int     `firestore:"release_year,omitempty" json:"release_year,omitempty"`
+	GameType    string  `firestore:"game_type,omitempty" json:"game_type,omitempty"` // IGDB game type, e.g. "Main Game" or "Remake"
+	Score       float64 `firestore:"score" json:"score"`                             // Match confidence, 0-1
 }
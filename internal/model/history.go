@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// StatusChange records one status transition of a game, oldest entries first when listed
+type StatusChange struct {
+	ID         string     `firestore:"id" json:"id"`
+	UserID     string     `firestore:"user_id" json:"user_id"`
+	GameID     string     `firestore:"game_id" json:"game_id"`
+	From       GameStatus `firestore:"from,omitempty" json:"from,omitempty"` // Empty when the game was created
+	To         GameStatus `firestore:"to" json:"to"`
+	DatePlayed *time.Time `firestore:"date_played,omitempty" json:"date_played,omitempty"`
+	ChangedAt  time.Time  `firestore:"changed_at" json:"changed_at"`
+}
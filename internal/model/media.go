@@ -0,0 +1,13 @@
+package model
+
+// Limits on how much media is kept per game
+const (
+	MaxScreenshots = 10
+	MaxVideos      = 5
+)
+
+// GameVideo is a trailer or other video for a game, hosted on YouTube
+type GameVideo struct {
+	Name      string `firestore:"name,omitempty" json:"name,omitempty"` // e.g. "Trailer" or "Gameplay Video"
+	YouTubeID string `firestore:"youtube_id" json:"youtube_id"`
+}
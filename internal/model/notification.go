@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+)
+
+// NotificationKind is what a notification is about
+type NotificationKind string
+
+const (
+	NotificationReleaseDay         NotificationKind = "release_day"          // A backlog game comes out today
+	NotificationReleaseDateChanged NotificationKind = "release_date_changed" // A backlog game's release date moved
+	NotificationTest               NotificationKind = "test"                 // Sent on request to check a channel
+)
+
+// ChannelType is how a notification is delivered
+type ChannelType string
+
+const (
+	ChannelWebhook ChannelType = "webhook" // JSON POST to any URL
+	ChannelDiscord ChannelType = "discord" // Discord incoming webhook
+	ChannelNtfy    ChannelType = "ntfy"    // ntfy topic URL, e.g. https://ntfy.sh/my-topic
+	ChannelGotify  ChannelType = "gotify"  // Gotify server URL with an application token
+	ChannelEmail   ChannelType = "email"   // Email through the server's SMTP relay
+)
+
+// NotificationChannel is one place a user's notifications are sent
+type NotificationChannel struct {
+	Type    ChannelType `firestore:"type" json:"type"`
+	URL     string      `firestore:"url,omitempty" json:"url,omitempty"`     // Webhook, Discord, ntfy topic or Gotify server URL
+	Token   string      `firestore:"token,omitempty" json:"token,omitempty"` // Gotify application token or ntfy access token
+	Email   string      `firestore:"email,omitempty" json:"email,omitempty"` // Recipient for email channels
+	Enabled bool        `firestore:"enabled" json:"enabled"`
+}
+
+// NotificationSettings are a user's notification preferences
+type NotificationSettings struct {
+	ReleaseDay         bool                  `firestore:"release_day" json:"release_day"`                   // Notify when a backlog game comes out
+	ReleaseDateChanges bool                  `firestore:"release_date_changes" json:"release_date_changes"` // Notify when a backlog game's release date moves
+	Channels           []NotificationChannel `firestore:"channels,omitempty" json:"channels,omitempty"`
+}
+
+// DeliveryStatus tracks a delivery through its retries
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending" // Not delivered yet; retried at NextAttemptAt
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed" // Gave up after the last attempt
+)
+
+// NotificationDelivery records one notification sent to one channel
+type NotificationDelivery struct {
+	ID            string              `firestore:"id" json:"id"` // Derived from the event and channel, so an event is delivered once
+	UserID        string              `firestore:"user_id" json:"user_id"`
+	GameID        string              `firestore:"game_id,omitempty" json:"game_id,omitempty"`
+	Kind          NotificationKind    `firestore:"kind" json:"kind"`
+	Title         string              `firestore:"title" json:"title"`
+	Message       string              `firestore:"message" json:"message"`
+	URL           string              `firestore:"url,omitempty" json:"url,omitempty"` // Link to the game, when there is one
+	Channel       NotificationChannel `firestore:"channel" json:"-"`                   // Copied so retries survive settings changes; holds secrets
+	ChannelType   ChannelType         `firestore:"channel_type" json:"channel_type"`
+	Status        DeliveryStatus      `firestore:"status" json:"status"`
+	Attempts      int                 `firestore:"attempts" json:"attempts"`
+	LastError     string              `firestore:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt *time.Time          `firestore:"next_attempt_at,omitempty" json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time           `firestore:"created_at" json:"created_at"`
+	SentAt        *time.Time          `firestore:"sent_at,omitempty" json:"sent_at,omitempty"`
+}
@@ -0,0 +1,73 @@
+package model
+
+// GameRef points at another IGDB game, which may or may not be in the library
+type GameRef struct {
+	IGDBID int    `firestore:"igdb_id" json:"igdb_id"`
+	Name   string `firestore:"name" json:"name"`
+}
+
+// RelationKind is how a game relates to its parent game
+type RelationKind string
+
+const (
+	RelationDLC                 RelationKind = "dlc"
+	RelationExpansion           RelationKind = "expansion"
+	RelationStandaloneExpansion RelationKind = "standalone_expansion"
+	RelationEpisode             RelationKind = "episode"
+	RelationSeason              RelationKind = "season"
+	RelationRemaster            RelationKind = "remaster"
+	RelationRemake              RelationKind = "remake"
+	RelationPart                RelationKind = "part" // Any other child game, e.g. a mod or update
+)
+
+// relationKinds maps IGDB game types to relation kinds and the label prefix used for them
+var relationKinds = map[string]struct {
+	kind  RelationKind
+	label string
+}{
+	"DLC":                  {RelationDLC, "DLC of"},
+	"Expansion":            {RelationExpansion, "Expansion of"},
+	"Standalone Expansion": {RelationStandaloneExpansion, "Standalone expansion of"},
+	"Episode":              {RelationEpisode, "Episode of"},
+	"Season":               {RelationSeason, "Season of"},
+	"Remaster":             {RelationRemaster, "Remaster of"},
+	"Remake":               {RelationRemake, "Remake of"},
+}
+
+// GameRelation describes a game's parent game, e.g. "DLC of The Witcher 3: Wild Hunt".
+// It is derived from the game's type and parent and filled in by the API, never stored.
+type GameRelation struct {
+	Kind            RelationKind `json:"kind"`
+	Label           string       `json:"label"`
+	Parent          GameRef      `json:"parent"`
+	ParentGameID    string       `json:"parent_game_id,omitempty"` // The parent's ID in the library
+	ParentInLibrary bool         `json:"parent_in_library"`
+	NeedsParent     bool         `json:"needs_parent"` // Only playable with the parent, so clients show it under the parent
+}
+
+// ParentRelation describes the game's relation to its parent game, or returns nil for
+// games without one. The library lookup fields are left empty.
+func (g *Game) ParentRelation() *GameRelation {
+	if g.ParentGame == nil || g.ParentGame.IGDBID == 0 {
+		return nil
+	}
+
+	relation := &GameRelation{Kind: RelationPart, Label: "Part of " + g.ParentGame.Name, Parent: *g.ParentGame}
+	if known, ok := relationKinds[g.GameType]; ok {
+		relation.Kind = known.kind
+		relation.Label = known.label + " " + g.ParentGame.Name
+	}
+	relation.NeedsParent = relation.Kind.NeedsParent()
+	return relation
+}
+
+// NeedsParent reports whether games of this kind are only playable with their parent
+// game, as DLC, expansions, episodes and seasons are
+func (k RelationKind) NeedsParent() bool {
+	switch k {
+	case RelationDLC, RelationExpansion, RelationEpisode, RelationSeason:
+		return true
+	default:
+		return false
+	}
+}
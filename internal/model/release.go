@@ -0,0 +1,206 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// IGDB release statuses worth telling apart; anything else (alpha, beta, early access,
+// offline) is a pre-release
+const (
+	ReleaseStatusFullRelease = "Full Release"
+	ReleaseStatusCancelled   = "Cancelled"
+)
+
+// DatePrecision is how exactly a release date is known
+type DatePrecision string
+
+const (
+	DatePrecisionDay     DatePrecision = "day"
+	DatePrecisionMonth   DatePrecision = "month"   // "Sep 2025"
+	DatePrecisionQuarter DatePrecision = "quarter" // "Q3 2025"
+	DatePrecisionYear    DatePrecision = "year"    // "2025"
+	DatePrecisionTBD     DatePrecision = "tbd"     // Announced without a date
+)
+
+// precisionRank orders precisions finest first, so "September 2025" sorts before "Q3 2025"
+// and "Q3 2025" before "2025" although they all end on the same day
+var precisionRank = map[DatePrecision]int{
+	DatePrecisionDay:     0,
+	DatePrecisionMonth:   1,
+	DatePrecisionQuarter: 2,
+	DatePrecisionYear:    3,
+	DatePrecisionTBD:     4,
+}
+
+// PlatformRelease is one IGDB release of a game: a platform in a region, with its own
+// date and status
+type PlatformRelease struct {
+	Platform  string        `firestore:"platform" json:"platform"`                       // Platform abbreviation, as in Game.Platforms
+	Region    string        `firestore:"region,omitempty" json:"region,omitempty"`       // e.g. "Europe", "North America", "Worldwide"
+	Date      *time.Time    `firestore:"date,omitempty" json:"date,omitempty"`           // Nil while IGDB has no date
+	Precision DatePrecision `firestore:"precision,omitempty" json:"precision,omitempty"` // How exactly Date is known; empty means day (or TBD without a date)
+	Label     string        `firestore:"label,omitempty" json:"label,omitempty"`         // Human-readable imprecise date, e.g. "Q3 2025"; empty for exact days
+	Status    string        `firestore:"status,omitempty" json:"status,omitempty"`       // e.g. "Alpha", "Early Access", "Full Release"; empty when IGDB doesn't say
+}
+
+// IsFullRelease reports whether the release is the final game rather than a pre-release.
+// Releases without a status are assumed final, as most of IGDB's are.
+func (r *PlatformRelease) IsFullRelease() bool {
+	return r.Status == "" || r.Status == ReleaseStatusFullRelease
+}
+
+// DatePrecision returns the release's precision, filling in the defaults for releases
+// stored without one
+func (r *PlatformRelease) DatePrecision() DatePrecision {
+	switch {
+	case r.Date == nil || r.Date.Equal(ReleaseDateSentinel):
+		return DatePrecisionTBD
+	case r.Precision == "":
+		return DatePrecisionDay
+	default:
+		return r.Precision
+	}
+}
+
+// PeriodEnd is the last moment the release may happen on: the end of its day, month,
+// quarter or year. TBD releases end at the release date sentinel.
+func (r *PlatformRelease) PeriodEnd() time.Time {
+	precision := r.DatePrecision()
+	if precision == DatePrecisionTBD {
+		return ReleaseDateSentinel
+	}
+	_, end := ReleasePeriod(*r.Date, precision)
+	return end.Add(-time.Second)
+}
+
+// Before orders releases by the end of their period, finer precision first, so an
+// imprecise "Q3 2025" sorts after every exact date in that quarter instead of at its start
+func (r *PlatformRelease) Before(other *PlatformRelease) bool {
+	a, b := r.PeriodEnd(), other.PeriodEnd()
+	if !a.Equal(b) {
+		return a.Before(b)
+	}
+	return precisionRank[r.DatePrecision()] < precisionRank[other.DatePrecision()]
+}
+
+// ReleasePeriod returns the start and (exclusive) end of the period a date of the given
+// precision stands for, in UTC
+func ReleasePeriod(date time.Time, precision DatePrecision) (time.Time, time.Time) {
+	date = date.UTC()
+	year, month := date.Year(), date.Month()
+
+	switch precision {
+	case DatePrecisionMonth:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	case DatePrecisionQuarter:
+		start := time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 3, 0)
+	case DatePrecisionYear:
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0)
+	default:
+		start := time.Date(year, month, date.Day(), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	}
+}
+
+// ReleaseDayKey names the UTC day of a release date, as stored in Game.ReleaseDays
+func ReleaseDayKey(date time.Time) string {
+	return date.UTC().Format(time.DateOnly)
+}
+
+// ReleaseLabel describes an imprecise release date the way IGDB does ("Sep 2025",
+// "Q3 2025", "2025", "TBD"); exact days have no label
+func ReleaseLabel(date *time.Time, precision DatePrecision) string {
+	if precision == DatePrecisionTBD || date == nil {
+		return "TBD"
+	}
+
+	d := date.UTC()
+	switch precision {
+	case DatePrecisionMonth:
+		return d.Format("Jan 2006")
+	case DatePrecisionQuarter:
+		return fmt.Sprintf("Q%d %d", (int(d.Month())-1)/3+1, d.Year())
+	case DatePrecisionYear:
+		return fmt.Sprintf("%d", d.Year())
+	default:
+		return ""
+	}
+}
+
+// Release returns the game's own (first) release date as a PlatformRelease without a
+// platform, with the sentinel date reported as TBD
+func (g *Game) Release() PlatformRelease {
+	release := PlatformRelease{
+		Precision: g.ReleasePrecision,
+		Label:     g.ReleaseLabel,
+	}
+	if g.HasReleaseDate() {
+		release.Date = g.ReleaseDate
+	}
+	return release
+}
+
+// ReleaseOn returns the game's release on platform: its earliest full release in any
+// region, or else its earliest pre-release. It returns nil when IGDB lists no dated
+// release for the platform.
+func (g *Game) ReleaseOn(platform string) *PlatformRelease {
+	var full, early *PlatformRelease
+	for i := range g.Releases {
+		release := &g.Releases[i]
+		if release.Platform != platform || release.Status == ReleaseStatusCancelled || release.DatePrecision() == DatePrecisionTBD {
+			continue
+		}
+		if release.IsFullRelease() {
+			full = earliest(full, release)
+		} else {
+			early = earliest(early, release)
+		}
+	}
+
+	if full != nil {
+		return full
+	}
+	return early
+}
+
+// ReleaseFor is the release lists sort the game by: the release on the game's preferred
+// platform (or defaultPlatform when it has none) if IGDB dates it, else the game's own
+// release date
+func (g *Game) ReleaseFor(defaultPlatform string) PlatformRelease {
+	platform := g.PreferredPlatform
+	if platform == "" {
+		platform = defaultPlatform
+	}
+	if platform != "" {
+		if release := g.ReleaseOn(platform); release != nil {
+			return *release
+		}
+	}
+	return g.Release()
+}
+
+// SortReleases orders releases by date (undated last), then platform and region
+func SortReleases(releases []PlatformRelease) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		a, b := &releases[i], &releases[j]
+		if a.Before(b) || b.Before(a) {
+			return a.Before(b)
+		}
+		if a.Platform != b.Platform {
+			return a.Platform < b.Platform
+		}
+		return a.Region < b.Region
+	})
+}
+
+func earliest(current, candidate *PlatformRelease) *PlatformRelease {
+	if current == nil || candidate.Before(current) {
+		return candidate
+	}
+	return current
+}
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// UserSettings holds per-user preferences, stored under the user's ID
+type UserSettings struct {
+	UserID            string               `firestore:"user_id" json:"user_id"`
+	PreferredPlatform string               `firestore:"preferred_platform,omitempty" json:"preferred_platform,omitempty"` // Default platform for release dates, e.g. "PS5"
+	CalendarToken     string               `firestore:"calendar_token,omitempty" json:"calendar_token,omitempty"`         // Secret for the iCalendar feed URL; empty disables the feed
+	Notifications     NotificationSettings `firestore:"notifications" json:"notifications"`
+	UpdatedAt         time.Time            `firestore:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+)
+
+type SyncTrigger string
+
+const (
+	SyncTriggerSchedule SyncTrigger = "schedule" // Periodic background run
+	SyncTriggerAdmin    SyncTrigger = "admin"    // Started through the admin API
+)
+
+type SyncRunStatus string
+
+const (
+	SyncRunQueued      SyncRunStatus = "queued" // Requested through the admin API, waiting for the instance holding the sync lease
+	SyncRunRunning     SyncRunStatus = "running"
+	SyncRunCompleted   SyncRunStatus = "completed"
+	SyncRunFailed      SyncRunStatus = "failed"      // The run could not start, e.g. the game query failed
+	SyncRunInterrupted SyncRunStatus = "interrupted" // Cut short by shutdown or by losing the sync lease
+)
+
+// SyncScope narrows a run to one user or one game; the zero value covers every game that is due
+type SyncScope struct {
+	UserID string `firestore:"user_id,omitempty" json:"user_id,omitempty"`
+	GameID string `firestore:"game_id,omitempty" json:"game_id,omitempty"`
+}
+
+// SyncGameError is a per-game failure collected during a run
+type SyncGameError struct {
+	GameID string `firestore:"game_id" json:"game_id"`
+	Title  string `firestore:"title" json:"title"`
+	Error  string `firestore:"error" json:"error"`
+}
+
+// SyncResult aggregates the outcome of one sync run
+type SyncResult struct {
+	Synced     int             `firestore:"synced" json:"synced"`       // Matched games whose metadata changed
+	Unchanged  int             `firestore:"unchanged" json:"unchanged"` // Matched games already up to date
+	Errors     int             `firestore:"errors" json:"errors"`
+	Matched    int             `firestore:"matched" json:"matched"` // Unmatched games automatically matched
+	Multiple   int             `firestore:"multiple" json:"multiple"`
+	NoMatch    int             `firestore:"no_match" json:"no_match"`
+	GameErrors []SyncGameError `firestore:"game_errors,omitempty" json:"game_errors,omitempty"` // Capped; Errors has the full count
+}
+
+// SyncRun records one run of the background sync
+type SyncRun struct {
+	ID          string        `firestore:"id" json:"id"`
+	Trigger     SyncTrigger   `firestore:"trigger" json:"trigger"`
+	RequestedBy string        `firestore:"requested_by,omitempty" json:"requested_by,omitempty"` // Admin user ID for admin runs
+	Scope       SyncScope     `firestore:"scope" json:"scope"`
+	Status      SyncRunStatus `firestore:"status" json:"status"`
+	Error       string        `firestore:"error,omitempty" json:"error,omitempty"`
+	StartedAt   time.Time     `firestore:"started_at" json:"started_at"` // When a queued run was requested, until it starts
+	FinishedAt  *time.Time    `firestore:"finished_at,omitempty" json:"finished_at,omitempty"`
+	SyncResult
+}
@@ -0,0 +1,54 @@
+package model
+
+import "fmt"
+
+// Pace picks which of a game's time-to-beat estimates to use
+type Pace string
+
+const (
+	PaceHastily    Pace = "hastily"    // Main story, rushed
+	PaceNormally   Pace = "normally"   // Main story and some extras
+	PaceCompletely Pace = "completely" // Everything
+)
+
+// ParsePace parses a pace name; empty means normally
+func ParsePace(value string) (Pace, error) {
+	switch pace := Pace(value); pace {
+	case "":
+		return PaceNormally, nil
+	case PaceHastily, PaceNormally, PaceCompletely:
+		return pace, nil
+	default:
+		return "", fmt.Errorf("unknown pace %q", value)
+	}
+}
+
+// TimeToBeat holds IGDB's crowd-sourced playtime estimates, in seconds. Zero means IGDB
+// has no estimate for that pace.
+type TimeToBeat struct {
+	Hastily    int `firestore:"hastily,omitempty" json:"hastily,omitempty"`
+	Normally   int `firestore:"normally,omitempty" json:"normally,omitempty"`
+	Completely int `firestore:"completely,omitempty" json:"completely,omitempty"`
+	Count      int `firestore:"count,omitempty" json:"count,omitempty"` // Submissions the estimates are based on
+}
+
+// Hours returns the estimate for a pace in hours, and false when there is none
+func (t *TimeToBeat) Hours(pace Pace) (float64, bool) {
+	if t == nil {
+		return 0, false
+	}
+
+	var seconds int
+	switch pace {
+	case PaceHastily:
+		seconds = t.Hastily
+	case PaceCompletely:
+		seconds = t.Completely
+	default:
+		seconds = t.Normally
+	}
+	if seconds <= 0 {
+		return 0, false
+	}
+	return float64(seconds) / 3600, true
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+)
+
+// WebhookEvent is the type of change a webhook subscription can listen for
+type WebhookEvent string
+
+const (
+	EventGameCreated         WebhookEvent = "game.created"
+	EventGameStatusChanged   WebhookEvent = "game.status_changed"
+	EventGameDeleted         WebhookEvent = "game.deleted"
+	EventGameMetadataUpdated WebhookEvent = "game.metadata_updated" // Matched, re-matched or refreshed from IGDB
+	EventPing                WebhookEvent = "ping"                  // Sent on request to check a subscription; always delivered
+	EventAll                 WebhookEvent = "*"                     // Subscribes to every event
+)
+
+// WebhookEvents lists the events subscriptions can name
+var WebhookEvents = []WebhookEvent{
+	EventGameCreated,
+	EventGameStatusChanged,
+	EventGameDeleted,
+	EventGameMetadataUpdated,
+}
+
+// IsValid reports whether a subscription can name the event
+func (e WebhookEvent) IsValid() bool {
+	if e == EventAll {
+		return true
+	}
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription is a user's endpoint for game change events
+type WebhookSubscription struct {
+	ID          string         `firestore:"id" json:"id"`
+	UserID      string         `firestore:"user_id" json:"user_id"`
+	URL         string         `firestore:"url" json:"url"`
+	Description string         `firestore:"description,omitempty" json:"description,omitempty"`
+	Events      []WebhookEvent `firestore:"events" json:"events"`
+	Secret      string         `firestore:"secret" json:"-"` // HMAC key; only shown when created or rotated
+	Active      bool           `firestore:"active" json:"active"`
+	CreatedAt   time.Time      `firestore:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `firestore:"updated_at" json:"updated_at"`
+}
+
+// Wants reports whether the subscription receives the event
+func (s *WebhookSubscription) Wants(event WebhookEvent) bool {
+	if event == EventPing {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == event || e == EventAll {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records one event sent to one subscription, through its retries
+type WebhookDelivery struct {
+	ID             string         `firestore:"id" json:"id"`
+	SubscriptionID string         `firestore:"subscription_id" json:"subscription_id"`
+	UserID         string         `firestore:"user_id" json:"user_id"`
+	EventID        string         `firestore:"event_id" json:"event_id"` // Shared by every subscription's delivery of the event
+	Event          WebhookEvent   `firestore:"event" json:"event"`
+	Payload        string         `firestore:"payload" json:"payload"` // The JSON body, signed and sent as is on every attempt
+	Status         DeliveryStatus `firestore:"status" json:"status"`
+	Attempts       int            `firestore:"attempts" json:"attempts"`
+	ResponseStatus int            `firestore:"response_status,omitempty" json:"response_status,omitempty"` // HTTP status of the last attempt
+	LastError      string         `firestore:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time     `firestore:"next_attempt_at,omitempty" json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time      `firestore:"created_at" json:"created_at"`
+	DeliveredAt    *time.Time     `firestore:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,144 @@
+// Package netguard keeps requests to user-supplied URLs (notification channels, webhook
+// subscriptions) away from the server's own network: loopback, private and link-local
+// addresses such as the cloud metadata server are refused unless explicitly allowed.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBlocked is returned when a URL or connection targets a refused address
+var ErrBlocked = errors.New("address is not allowed")
+
+// blockedPrefixes are special-purpose ranges not covered by netip's Is* checks
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // Reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, which can reach IPv4 private ranges
+	netip.MustParsePrefix("64:ff9b:1::/48"), // Local-use NAT64
+	netip.MustParsePrefix("2002::/16"),      // 6to4, which embeds an IPv4 address
+	netip.MustParsePrefix("fec0::/10"),      // Deprecated site-local
+}
+
+// Guard decides which addresses outbound requests may reach. The zero value refuses every
+// non-public address.
+type Guard struct {
+	allowed []netip.Prefix
+}
+
+// New returns a guard that also lets requests reach the allowed networks, e.g. a
+// self-hosted ntfy or a request bin on the local network used as a stand-in
+func New(allowed []netip.Prefix) *Guard {
+	return &Guard{allowed: allowed}
+}
+
+// Allowed reports whether requests may reach addr
+func (g *Guard) Allowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range g.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL checks that a URL is http(s) with a host, and that the host isn't a refused
+// address or a name for this machine. Names are only resolved when connecting, so a
+// name that later points somewhere refused is still stopped by the client's dialer.
+func (g *Guard) CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http(s) URL")
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !g.Allowed(addr) {
+			return fmt.Errorf("%s: %w", host, ErrBlocked)
+		}
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		if !g.Allowed(netip.MustParseAddr("127.0.0.1")) {
+			return fmt.Errorf("%s: %w", host, ErrBlocked)
+		}
+	}
+	return nil
+}
+
+// Dialer returns a dialer that checks every address it connects to, after DNS
+// resolution, so a name can't be pointed at a refused address between CheckURL and
+// the request
+func (g *Guard) Dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%s: %w", address, ErrBlocked)
+			}
+			if !g.Allowed(addrPort.Addr()) {
+				return fmt.Errorf("%s: %w", addrPort.Addr(), ErrBlocked)
+			}
+			return nil
+		},
+	}
+}
+
+// Client returns an HTTP client whose connections, redirects included, go through the
+// guarded dialer. Environment proxies are ignored, since they would make the connection
+// on the client's behalf.
+func (g *Guard) Client(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = g.Dialer(timeout).DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// ParsePrefixes parses a comma-separated list of networks ("10.0.0.0/8") or single
+// addresses ("127.0.0.1")
+func ParsePrefixes(list string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.Contains(item, "/") {
+			prefix, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid network %q: %w", item, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", item, err)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
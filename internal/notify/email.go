@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// Email sends the message as a plain-text email through an SMTP relay. STARTTLS is used
+// whenever the relay offers it.
+type Email struct {
+	SMTP SMTPConfig
+	To   string
+}
+
+func (n *Email) Send(ctx context.Context, msg *Message) error {
+	addr := net.JoinHostPort(n.SMTP.Host, strconv.Itoa(n.SMTP.Port))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	// net/smtp doesn't take a context, so bound the whole conversation by its deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.SMTP.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.SMTP.Host}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+		}
+	}
+	if n.SMTP.Username != "" {
+		auth := smtp.PlainAuth("", n.SMTP.Username, n.SMTP.Password, n.SMTP.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	// SMTP_FROM may carry a display name ("Game Tracker <games@example.com>")
+	from := n.SMTP.From
+	if addr, err := mail.ParseAddress(from); err == nil {
+		from = addr.Address
+	}
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	if err := client.Rcpt(n.To); err != nil {
+		return fmt.Errorf("SMTP server rejected recipient: %w", err)
+	}
+
+	wc, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := wc.Write(n.compose(msg)); err != nil {
+		wc.Close()
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// compose renders the message as a MIME email
+func (n *Email) compose(msg *Message) []byte {
+	var b bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", name, value)
+	}
+
+	header("From", n.SMTP.From)
+	header("To", n.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Title))
+	header("Date", msg.CreatedAt.Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@game-tracker>", messageToken()))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+
+	text := msg.Body
+	if msg.URL != "" {
+		text += "\n\n" + msg.URL
+	}
+
+	qp := quotedprintable.NewWriter(&b)
+	_, _ = qp.Write([]byte(text))
+	_ = qp.Close()
+
+	return b.Bytes()
+}
+
+func messageToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,246 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSession is what the fake SMTP server received in one conversation
+type smtpSession struct {
+	auth string // Decoded AUTH PLAIN credentials, "\x00user\x00password"
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP is a minimal plaintext SMTP server that accepts a single conversation.
+// Recipients in reject are refused with a 550.
+type fakeSMTP struct {
+	listener net.Listener
+	reject   map[string]bool
+	done     chan smtpSession
+}
+
+func newFakeSMTP(t *testing.T, reject ...string) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTP{listener: listener, reject: map[string]bool{}, done: make(chan smtpSession, 1)}
+	for _, rcpt := range reject {
+		s.reject[rcpt] = true
+	}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTP) config() SMTPConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "Game Tracker <games@example.com>"}
+}
+
+// session waits for the conversation to end and returns what was received
+func (s *fakeSMTP) session(t *testing.T) smtpSession {
+	t.Helper()
+	select {
+	case session := <-s.done:
+		return session
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the SMTP conversation")
+		return smtpSession{}
+	}
+}
+
+func (s *fakeSMTP) serve() {
+	var session smtpSession
+	defer func() { s.done <- session }()
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				reply("501 syntax error")
+				continue
+			}
+			decoded, _ := base64.StdEncoding.DecodeString(fields[2])
+			session.auth = string(decoded)
+			reply("235 authenticated")
+		case "MAIL":
+			session.from = addressArg(line)
+			reply("250 ok")
+		case "RCPT":
+			rcpt := addressArg(line)
+			if s.reject[rcpt] {
+				reply("550 no such user")
+				continue
+			}
+			session.to = append(session.to, rcpt)
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			session.data = data.String()
+			reply("250 queued")
+		case "RSET", "NOOP":
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+// addressArg extracts the address from "MAIL FROM:<a@b>" or "RCPT TO:<a@b>"
+func addressArg(line string) string {
+	start, end := strings.Index(line, "<"), strings.Index(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func TestEmailSend(t *testing.T) {
+	server := newFakeSMTP(t)
+
+	msg := testMessage()
+	msg.Title = "Out today: Pokémon Legends: Z-A"
+	msg.Body = "Pokémon Legends: Z-A came out today on Nintendo Switch 2."
+	n := &Email{SMTP: server.config(), To: "player@example.com"}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	session := server.session(t)
+	if session.auth != "" {
+		t.Errorf("authenticated without a username: %q", session.auth)
+	}
+	if session.from != "games@example.com" {
+		t.Errorf("MAIL FROM = %q, want the bare address games@example.com", session.from)
+	}
+	if len(session.to) != 1 || session.to[0] != "player@example.com" {
+		t.Errorf("RCPT TO = %v, want [player@example.com]", session.to)
+	}
+
+	email, err := mail.ReadMessage(strings.NewReader(session.data))
+	if err != nil {
+		t.Fatalf("parsing email: %v", err)
+	}
+	if got := email.Header.Get("From"); got != "Game Tracker <games@example.com>" {
+		t.Errorf("From = %q", got)
+	}
+	if got := email.Header.Get("To"); got != "player@example.com" {
+		t.Errorf("To = %q", got)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(email.Header.Get("Subject"))
+	if err != nil || subject != msg.Title {
+		t.Errorf("Subject decodes to %q (%v), want %q", subject, err, msg.Title)
+	}
+	if date, err := email.Header.Date(); err != nil || !date.Equal(msg.CreatedAt) {
+		t.Errorf("Date = %v (%v), want %v", date, err, msg.CreatedAt)
+	}
+	if email.Header.Get("Message-ID") == "" {
+		t.Error("missing Message-ID")
+	}
+	if got := email.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+		t.Errorf("Content-Transfer-Encoding = %q, want quoted-printable", got)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(email.Body))
+	if err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+	// DATA always ends on a line break before the terminating dot
+	want := msg.Body + "\n\n" + msg.URL
+	if got := strings.TrimSuffix(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n"); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestEmailSendAuthenticates(t *testing.T) {
+	server := newFakeSMTP(t)
+
+	config := server.config()
+	config.Username, config.Password = "relay-user", "relay-pass"
+	n := &Email{SMTP: config, To: "player@example.com"}
+	if err := n.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if session := server.session(t); session.auth != "\x00relay-user\x00relay-pass" {
+		t.Errorf("AUTH PLAIN credentials = %q", session.auth)
+	}
+}
+
+func TestEmailSendRejectedRecipient(t *testing.T) {
+	server := newFakeSMTP(t, "nobody@example.com")
+
+	n := &Email{SMTP: server.config(), To: "nobody@example.com"}
+	err := n.Send(context.Background(), testMessage())
+	if err == nil || !strings.Contains(err.Error(), "rejected recipient") {
+		t.Fatalf("Send error = %v, want a rejected recipient error", err)
+	}
+
+	if session := server.session(t); session.data != "" {
+		t.Errorf("email was sent despite the rejected recipient: %q", session.data)
+	}
+}
+
+func TestEmailSendUnreachableServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	n := &Email{SMTP: SMTPConfig{Host: "127.0.0.1", Port: port, From: "games@example.com"}, To: "player@example.com"}
+	if err := n.Send(context.Background(), testMessage()); err == nil || !strings.Contains(err.Error(), "failed to connect") {
+		t.Errorf("Send error = %v, want a connection error", err)
+	}
+}
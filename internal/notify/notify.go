@@ -0,0 +1,123 @@
+// Package notify delivers release notifications to the channels users configure:
+// generic webhooks, Discord, ntfy, Gotify and email
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"game-tracker/internal/model"
+	"game-tracker/internal/netguard"
+)
+
+// Message is what a notifier sends
+type Message struct {
+	ID        string                 `json:"id"` // Delivery ID; the same across retries, so receivers can drop duplicates
+	Kind      model.NotificationKind `json:"kind"`
+	Title     string                 `json:"title"`
+	Body      string                 `json:"message"`
+	URL       string                 `json:"url,omitempty"`
+	GameID    string                 `json:"game_id,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// Notifier sends messages to one channel
+type Notifier interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMTPConfig is the mail relay email channels send through; an empty Host disables them
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Empty sends without authentication, e.g. to a local relay
+	Password string
+	From     string
+}
+
+// StatusError is a non-2xx response from an HTTP channel. The response body isn't kept:
+// delivery errors are shown to the user, and the body could be anything the URL serves.
+type StatusError struct {
+	Channel    model.ChannelType
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned HTTP %d", e.Channel, e.StatusCode)
+}
+
+// Permanent reports whether retrying can't help: the channel rejected the request itself
+// (a deleted webhook, a bad token) rather than failing or rate limiting
+func Permanent(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	code := statusErr.StatusCode
+	return code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+}
+
+// post sends a request and turns non-2xx responses into a StatusError
+func post(ctx context.Context, client *http.Client, channel model.ChannelType, req *http.Request) error {
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", channel, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return &StatusError{Channel: channel, StatusCode: resp.StatusCode}
+}
+
+// maxDrainBytes is how much of a response is read so the connection can be reused
+const maxDrainBytes = 64 << 10
+
+// NormalizeChannel trims a channel's settings and checks it can be sent to. URLs must not
+// point at addresses guard refuses; email channels need smtpConfigured.
+func NormalizeChannel(channel *model.NotificationChannel, guard *netguard.Guard, smtpConfigured bool) error {
+	channel.Type = model.ChannelType(strings.ToLower(strings.TrimSpace(string(channel.Type))))
+	channel.URL = strings.TrimSpace(channel.URL)
+	channel.Token = strings.TrimSpace(channel.Token)
+	channel.Email = strings.TrimSpace(channel.Email)
+
+	switch channel.Type {
+	case model.ChannelWebhook, model.ChannelDiscord, model.ChannelNtfy:
+		return validateURL(channel, guard)
+	case model.ChannelGotify:
+		if channel.Token == "" {
+			return fmt.Errorf("gotify channels need an application token")
+		}
+		return validateURL(channel, guard)
+	case model.ChannelEmail:
+		if !smtpConfigured {
+			return fmt.Errorf("email notifications are not configured on this server")
+		}
+		addr, err := mail.ParseAddress(channel.Email)
+		if err != nil {
+			return fmt.Errorf("invalid email address %q", channel.Email)
+		}
+		channel.Email = addr.Address
+		return nil
+	default:
+		return fmt.Errorf("unknown channel type %q", channel.Type)
+	}
+}
+
+func validateURL(channel *model.NotificationChannel, guard *netguard.Guard) error {
+	if err := guard.CheckURL(channel.URL); err != nil {
+		if errors.Is(err, netguard.ErrBlocked) {
+			return fmt.Errorf("%s channels can't send to private or local addresses (%v)", channel.Type, err)
+		}
+		return fmt.Errorf("%s channels need an http(s) URL", channel.Type)
+	}
+	return nil
+}
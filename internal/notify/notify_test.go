@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"game-tracker/internal/model"
+	"game-tracker/internal/netguard"
+)
+
+func TestServiceRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer server.Close()
+
+	s := NewService(nil, Config{})
+	n, err := s.notifier(model.NotificationChannel{Type: model.ChannelWebhook, URL: server.URL})
+	if err != nil {
+		t.Fatalf("notifier: %v", err)
+	}
+	err = n.Send(context.Background(), testMessage())
+	if !errors.Is(err, netguard.ErrBlocked) {
+		t.Errorf("Send error = %v, want netguard.ErrBlocked", err)
+	}
+}
+
+func TestServiceSendsToAllowedNetworks(t *testing.T) {
+	received := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer server.Close()
+
+	s := NewService(nil, Config{AllowedNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}})
+	n, err := s.notifier(model.NotificationChannel{Type: model.ChannelWebhook, URL: server.URL})
+	if err != nil {
+		t.Fatalf("notifier: %v", err)
+	}
+	if err := n.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if !received {
+		t.Error("webhook was not delivered")
+	}
+}
+
+func TestNormalizeChannel(t *testing.T) {
+	guard := netguard.New(nil)
+
+	tests := []struct {
+		name    string
+		channel model.NotificationChannel
+		smtp    bool
+		wantErr bool
+	}{
+		{"webhook", model.NotificationChannel{Type: " Webhook ", URL: " https://example.com/hook "}, false, false},
+		{"loopback webhook", model.NotificationChannel{Type: model.ChannelWebhook, URL: "http://127.0.0.1:8080/hook"}, false, true},
+		{"metadata endpoint", model.NotificationChannel{Type: model.ChannelDiscord, URL: "http://169.254.169.254/latest"}, false, true},
+		{"private ntfy", model.NotificationChannel{Type: model.ChannelNtfy, URL: "http://10.0.0.5/games"}, false, true},
+		{"not http", model.NotificationChannel{Type: model.ChannelWebhook, URL: "ftp://example.com/hook"}, false, true},
+		{"gotify without token", model.NotificationChannel{Type: model.ChannelGotify, URL: "https://gotify.example.com"}, false, true},
+		{"gotify", model.NotificationChannel{Type: model.ChannelGotify, URL: "https://gotify.example.com", Token: "app"}, false, false},
+		{"email without smtp", model.NotificationChannel{Type: model.ChannelEmail, Email: "player@example.com"}, false, true},
+		{"email", model.NotificationChannel{Type: model.ChannelEmail, Email: "Player <player@example.com>"}, true, false},
+		{"bad email", model.NotificationChannel{Type: model.ChannelEmail, Email: "not an address"}, true, true},
+		{"unknown type", model.NotificationChannel{Type: "pager"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := tt.channel
+			err := NormalizeChannel(&channel, guard, tt.smtp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeChannel error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	channel := model.NotificationChannel{Type: model.ChannelEmail, Email: " Player <player@example.com> "}
+	if err := NormalizeChannel(&channel, guard, true); err != nil || channel.Email != "player@example.com" {
+		t.Errorf("email normalized to %q (%v), want player@example.com", channel.Email, err)
+	}
+	channel = model.NotificationChannel{Type: " Webhook ", URL: " https://example.com/hook "}
+	if err := NormalizeChannel(&channel, guard, false); err != nil || channel.Type != model.ChannelWebhook || channel.URL != "https://example.com/hook" {
+		t.Errorf("webhook normalized to %+v (%v)", channel, err)
+	}
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"game-tracker/internal/model"
+)
+
+// Ntfy publishes the message to an ntfy topic URL (https://ntfy.sh/topic or a
+// self-hosted server)
+type Ntfy struct {
+	URL    string
+	Token  string // Access token for protected topics; optional
+	Client *http.Client
+}
+
+func (n *Ntfy) Send(ctx context.Context, msg *Message) error {
+	req, err := http.NewRequest(http.MethodPost, n.URL, strings.NewReader(msg.Body))
+	if err != nil {
+		return fmt.Errorf("invalid ntfy URL: %w", err)
+	}
+	// Headers must be ASCII; ntfy decodes RFC 2047 encoded words
+	req.Header.Set("Title", mime.QEncoding.Encode("utf-8", msg.Title))
+	req.Header.Set("Tags", "video_game")
+	if msg.URL != "" {
+		req.Header.Set("Click", msg.URL)
+	}
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	return post(ctx, n.Client, model.ChannelNtfy, req)
+}
+
+// Gotify sends the message to a Gotify server as an application
+type Gotify struct {
+	URL    string // Server URL; the message endpoint is appended
+	Token  string // Application token
+	Client *http.Client
+}
+
+type gotifyPayload struct {
+	Title    string         `json:"title"`
+	Message  string         `json:"message"`
+	Priority int            `json:"priority"`
+	Extras   map[string]any `json:"extras,omitempty"`
+}
+
+// gotifyPriority shows the message as a notification on Gotify's Android app
+const gotifyPriority = 5
+
+func (n *Gotify) Send(ctx context.Context, msg *Message) error {
+	payload := gotifyPayload{
+		Title:    msg.Title,
+		Message:  msg.Body,
+		Priority: gotifyPriority,
+	}
+	if msg.URL != "" {
+		payload.Extras = map[string]any{
+			"client::notification": map[string]any{"click": map[string]string{"url": msg.URL}},
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode gotify payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(n.URL, "/")+"/message", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid gotify URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", n.Token)
+
+	return post(ctx, n.Client, model.ChannelGotify, req)
+}
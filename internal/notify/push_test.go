@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNtfySend(t *testing.T) {
+	var body string
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/games" {
+			t.Errorf("path = %s, want /games", r.URL.Path)
+		}
+		data, _ := io.ReadAll(r.Body)
+		body, header = string(data), r.Header
+	}))
+	defer server.Close()
+
+	msg := testMessage()
+	msg.Title = "Out today: Pokémon Legends: Z-A"
+	n := &Ntfy{URL: server.URL + "/games", Token: "tk_secret", Client: server.Client()}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if body != msg.Body {
+		t.Errorf("body = %q, want %q", body, msg.Body)
+	}
+	title, err := new(mime.WordDecoder).DecodeHeader(header.Get("Title"))
+	if err != nil || title != msg.Title {
+		t.Errorf("Title header decodes to %q (%v), want %q", title, err, msg.Title)
+	}
+	for name, want := range map[string]string{
+		"Tags":          "video_game",
+		"Click":         msg.URL,
+		"Authorization": "Bearer tk_secret",
+	} {
+		if got := header.Get(name); got != want {
+			t.Errorf("%s header = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNtfySendWithoutToken(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	n := &Ntfy{URL: server.URL, Client: server.Client()}
+	if err := n.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if authorization != "" {
+		t.Errorf("Authorization header = %q, want none", authorization)
+	}
+}
+
+func TestGotifySend(t *testing.T) {
+	var got gotifyPayload
+	var key string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/message" {
+			t.Errorf("path = %s, want /message", r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		key = r.Header.Get("X-Gotify-Key")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	msg := testMessage()
+	n := &Gotify{URL: server.URL + "/", Token: "app-token", Client: server.Client()}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if key != "app-token" {
+		t.Errorf("X-Gotify-Key = %q, want app-token", key)
+	}
+	if got.Title != msg.Title || got.Message != msg.Body || got.Priority != gotifyPriority {
+		t.Errorf("payload = %+v", got)
+	}
+	notification, _ := got.Extras["client::notification"].(map[string]any)
+	click, _ := notification["click"].(map[string]any)
+	if click["url"] != msg.URL {
+		t.Errorf("click URL = %v, want %s", click["url"], msg.URL)
+	}
+}
+
+func TestGotifySendStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	n := &Gotify{URL: server.URL, Token: "wrong", Client: server.Client()}
+	err := n.Send(context.Background(), testMessage())
+	if err == nil || err.Error() != "gotify returned HTTP 401" {
+		t.Errorf("Send error = %v, want gotify returned HTTP 401", err)
+	}
+	if !Permanent(err) {
+		t.Error("a rejected token should not be retried")
+	}
+}
@@ -0,0 +1,449 @@
+package notify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"reflect"
+	"time"
+
+	"game-tracker/internal/database"
+	"game-tracker/internal/model"
+	"game-tracker/internal/netguard"
+)
+
+// Config tunes delivery
+type Config struct {
+	SMTP            SMTPConfig
+	MaxAttempts     int            // Attempts per delivery before giving up
+	Timeout         time.Duration  // Upper bound for a single attempt
+	AllowedNetworks []netip.Prefix // Private networks channels may send to, e.g. for local stand-ins
+}
+
+// Retry delays for failed deliveries; retries run with the background sync, so in
+// practice they are also at least one sync interval apart
+const (
+	retryBase = 15 * time.Minute
+	retryMax  = 24 * time.Hour
+)
+
+// releaseLookback is how long after a release day it is still announced, so runs that
+// missed the day (the worker was down, or the interval skipped past it) catch up
+const releaseLookback = 7 * 24 * time.Hour
+
+// Service turns release events into deliveries to the users' channels and retries
+// the ones that failed
+type Service struct {
+	db     *database.Client
+	config Config
+	guard  *netguard.Guard
+	client *http.Client
+}
+
+func NewService(db *database.Client, config Config) *Service {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 5
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.SMTP.Port == 0 {
+		config.SMTP.Port = 587
+	}
+
+	guard := netguard.New(config.AllowedNetworks)
+	return &Service{
+		db:     db,
+		config: config,
+		guard:  guard,
+		client: guard.Client(config.Timeout),
+	}
+}
+
+// EmailEnabled reports whether the server can send email notifications
+func (s *Service) EmailEnabled() bool {
+	return s.config.SMTP.Host != ""
+}
+
+// NormalizeChannel trims and validates a channel a user wants to add
+func (s *Service) NormalizeChannel(channel *model.NotificationChannel) error {
+	return NormalizeChannel(channel, s.guard, s.EmailEnabled())
+}
+
+// notifier builds the notifier for a channel
+func (s *Service) notifier(channel model.NotificationChannel) (Notifier, error) {
+	switch channel.Type {
+	case model.ChannelWebhook:
+		return &Webhook{URL: channel.URL, Client: s.client}, nil
+	case model.ChannelDiscord:
+		return &Discord{URL: channel.URL, Client: s.client}, nil
+	case model.ChannelNtfy:
+		return &Ntfy{URL: channel.URL, Token: channel.Token, Client: s.client}, nil
+	case model.ChannelGotify:
+		return &Gotify{URL: channel.URL, Token: channel.Token, Client: s.client}, nil
+	case model.ChannelEmail:
+		if !s.EmailEnabled() {
+			return nil, fmt.Errorf("email notifications are not configured on this server")
+		}
+		return &Email{SMTP: s.config.SMTP, To: channel.Email}, nil
+	default:
+		return nil, fmt.Errorf("unknown channel type %q", channel.Type)
+	}
+}
+
+// event is something to tell a user about, before it is fanned out to their channels
+type event struct {
+	key     string // Identifies the event, so it is delivered once per channel
+	kind    model.NotificationKind
+	gameID  string
+	title   string
+	message string
+	url     string
+}
+
+// ReleaseDateChanged notifies the owner of a Backlog or Break game when a refresh moved
+// the release date they see for it (on their preferred platform). before is the game as
+// it was before the refresh.
+func (s *Service) ReleaseDateChanged(ctx context.Context, before, after *model.Game) error {
+	if !onBacklog(after) {
+		return nil
+	}
+	// Skip the settings lookup for the common case of nothing release-related changing
+	if sameRelease(before.Release(), after.Release()) && reflect.DeepEqual(before.Releases, after.Releases) {
+		return nil
+	}
+
+	settings, err := s.db.GetSettings(ctx, after.UserID)
+	if err != nil {
+		return err
+	}
+	if !settings.Notifications.ReleaseDateChanges {
+		return nil
+	}
+
+	old := before.ReleaseFor(settings.PreferredPlatform)
+	release := after.ReleaseFor(settings.PreferredPlatform)
+	if sameRelease(old, release) {
+		return nil
+	}
+
+	from, to := describeRelease(old), describeRelease(release)
+	message := fmt.Sprintf("%s%s moved from %s to %s.", after.Title, onPlatform(release), from, to)
+	if old.DatePrecision() == model.DatePrecisionTBD {
+		message = fmt.Sprintf("%s%s now has a release date: %s.", after.Title, onPlatform(release), to)
+	}
+
+	return s.notify(ctx, settings, event{
+		key:     fmt.Sprintf("%s|%s|%s|%s", after.ID, model.NotificationReleaseDateChanged, from, to),
+		kind:    model.NotificationReleaseDateChanged,
+		gameID:  after.ID,
+		title:   "New release date: " + after.Title,
+		message: message,
+		url:     gameURL(after),
+	})
+}
+
+// ReleaseDays notifies the owners of Backlog and Break games that came out on their
+// preferred platform within the last releaseLookback, so a release is announced even
+// when no sync ran on the day. Only games with a release on one of those days are
+// loaded. Each release is announced once per game, platform and release status;
+// failures for one game are logged and don't stop the others.
+func (s *Service) ReleaseDays(ctx context.Context, now time.Time) error {
+	games, err := s.db.GetGamesReleasedOn(ctx, lookbackDays(now))
+	if err != nil {
+		return err
+	}
+
+	settingsByUser := make(map[string]*model.UserSettings)
+	for _, game := range games {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !releasedRecently(game, now) {
+			continue
+		}
+
+		settings, ok := settingsByUser[game.UserID]
+		if !ok {
+			settings, err = s.db.GetSettings(ctx, game.UserID)
+			if err != nil {
+				log.Printf("ERROR: Failed to load notification settings for user %s: %v", game.UserID, err)
+				continue
+			}
+			settingsByUser[game.UserID] = settings
+		}
+
+		if err := s.releaseDay(ctx, settings, game, now); err != nil {
+			log.Printf("ERROR: Failed to send release day notification for '%s': %v", game.Title, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) releaseDay(ctx context.Context, settings *model.UserSettings, game *model.Game, now time.Time) error {
+	if !settings.Notifications.ReleaseDay {
+		return nil
+	}
+
+	release := game.ReleaseFor(settings.PreferredPlatform)
+	if release.DatePrecision() != model.DatePrecisionDay || !withinLookback(*release.Date, now) {
+		return nil
+	}
+
+	title := "Out today: " + game.Title
+	when := "today"
+	if !sameDay(*release.Date, now) {
+		title = "Out now: " + game.Title
+		when = "on " + describeRelease(release)
+	}
+	message := fmt.Sprintf("%s came out %s%s.", game.Title, when, onPlatform(release))
+	if !release.IsFullRelease() {
+		message = fmt.Sprintf("%s entered %s %s%s.", game.Title, release.Status, when, onPlatform(release))
+	}
+
+	return s.notify(ctx, settings, event{
+		key:     fmt.Sprintf("%s|%s|%s|%s", game.ID, model.NotificationReleaseDay, release.Platform, release.Status),
+		kind:    model.NotificationReleaseDay,
+		gameID:  game.ID,
+		title:   title,
+		message: message,
+		url:     gameURL(game),
+	})
+}
+
+// SendTest sends a test notification to each of the user's enabled channels and returns
+// the deliveries, so the user can see which channels work
+func (s *Service) SendTest(ctx context.Context, settings *model.UserSettings) ([]*model.NotificationDelivery, error) {
+	return s.fanOut(ctx, settings, event{
+		key:     fmt.Sprintf("%s|%s|%d", settings.UserID, model.NotificationTest, time.Now().UnixNano()),
+		kind:    model.NotificationTest,
+		title:   "Game Tracker test notification",
+		message: "Notifications from Game Tracker reach this channel.",
+	})
+}
+
+func (s *Service) notify(ctx context.Context, settings *model.UserSettings, e event) error {
+	deliveries, err := s.fanOut(ctx, settings, e)
+	for _, d := range deliveries {
+		log.Printf("Notification %s for user %s via %s: %s", e.kind, settings.UserID, d.ChannelType, d.Status)
+	}
+	return err
+}
+
+// fanOut records a delivery of e for every enabled channel that hasn't had it yet and
+// makes the first attempt at each
+func (s *Service) fanOut(ctx context.Context, settings *model.UserSettings, e event) ([]*model.NotificationDelivery, error) {
+	var deliveries []*model.NotificationDelivery
+
+	for _, channel := range settings.Notifications.Channels {
+		if !channel.Enabled {
+			continue
+		}
+
+		// Due straight away, so the retry picks it up if the first attempt never finishes
+		now := time.Now()
+		delivery := &model.NotificationDelivery{
+			ID:            deliveryID(settings.UserID, e.key, channel),
+			UserID:        settings.UserID,
+			GameID:        e.gameID,
+			Kind:          e.kind,
+			Title:         e.title,
+			Message:       e.message,
+			URL:           e.url,
+			Channel:       channel,
+			ChannelType:   channel.Type,
+			Status:        model.DeliveryPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		}
+
+		created, err := s.db.CreateDelivery(ctx, delivery)
+		if err != nil {
+			return deliveries, err
+		}
+		if !created {
+			continue
+		}
+
+		s.attempt(ctx, delivery)
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// RetryDue retries pending deliveries whose next attempt is due, returning how many
+// were sent and how many were given up on
+func (s *Service) RetryDue(ctx context.Context, now time.Time) (sent, failed int, err error) {
+	deliveries, err := s.db.GetDueDeliveries(ctx, now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		s.attempt(ctx, delivery)
+		switch delivery.Status {
+		case model.DeliverySent:
+			sent++
+		case model.DeliveryFailed:
+			failed++
+			log.Printf("Giving up on %s notification for user %s via %s after %d attempts: %s",
+				delivery.Kind, delivery.UserID, delivery.ChannelType, delivery.Attempts, delivery.LastError)
+		}
+	}
+
+	return sent, failed, nil
+}
+
+// attempt sends a delivery once and records the outcome, scheduling a retry with
+// exponential backoff when the channel may recover. Test notifications aren't retried:
+// the user is waiting for their result.
+func (s *Service) attempt(ctx context.Context, delivery *model.NotificationDelivery) {
+	err := s.send(ctx, delivery)
+	now := time.Now()
+	delivery.Attempts++
+
+	switch {
+	case err == nil:
+		delivery.Status = model.DeliverySent
+		delivery.SentAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	case Permanent(err) || delivery.Kind == model.NotificationTest || delivery.Attempts >= s.config.MaxAttempts:
+		delivery.Status = model.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = err.Error()
+	default:
+		next := now.Add(retryBackoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = err.Error()
+	}
+
+	// Record the outcome even when the attempt ran out of time
+	if err := s.db.SaveDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		log.Printf("ERROR: Failed to record notification delivery %s: %v", delivery.ID, err)
+	}
+}
+
+func (s *Service) send(ctx context.Context, delivery *model.NotificationDelivery) error {
+	notifier, err := s.notifier(delivery.Channel)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	return notifier.Send(ctx, &Message{
+		ID:        delivery.ID,
+		Kind:      delivery.Kind,
+		Title:     delivery.Title,
+		Body:      delivery.Message,
+		URL:       delivery.URL,
+		GameID:    delivery.GameID,
+		CreatedAt: delivery.CreatedAt,
+	})
+}
+
+// retryBackoff is the wait after the nth failed attempt: doubling from retryBase, capped
+// at retryMax
+func retryBackoff(attempts int) time.Duration {
+	backoff := retryBase
+	for i := 1; i < attempts && backoff < retryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, retryMax)
+}
+
+// deliveryID derives a delivery's ID from the event and the channel's destination, so
+// retried syncs and reordered channels don't deliver an event twice
+func deliveryID(userID, key string, channel model.NotificationChannel) string {
+	sum := sha256.Sum256([]byte(userID + "\x00" + key + "\x00" + string(channel.Type) + "\x00" + channel.URL + "\x00" + channel.Email))
+	return hex.EncodeToString(sum[:16])
+}
+
+func onBacklog(game *model.Game) bool {
+	return game.Status == model.StatusBacklog || game.Status == model.StatusBreak
+}
+
+// releasedRecently reports whether any of the game's releases is dated to a day within
+// the lookback window, before the settings are loaded to pick the one that counts
+func releasedRecently(game *model.Game, now time.Time) bool {
+	own := game.Release()
+	if own.DatePrecision() == model.DatePrecisionDay && withinLookback(*own.Date, now) {
+		return true
+	}
+	for i := range game.Releases {
+		release := &game.Releases[i]
+		if release.DatePrecision() == model.DatePrecisionDay && withinLookback(*release.Date, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// withinLookback reports whether the day of date has started and ended no more than
+// releaseLookback before now
+func withinLookback(date, now time.Time) bool {
+	start, end := model.ReleasePeriod(date, model.DatePrecisionDay)
+	return !now.Before(start) && end.After(now.Add(-releaseLookback))
+}
+
+// lookbackDays lists the days withinLookback can accept, today first
+func lookbackDays(now time.Time) []string {
+	var days []string
+	for day := now.UTC(); !day.Before(now.Add(-releaseLookback)); day = day.AddDate(0, 0, -1) {
+		days = append(days, model.ReleaseDayKey(day))
+	}
+	return days
+}
+
+func sameDay(date, now time.Time) bool {
+	start, end := model.ReleasePeriod(date, model.DatePrecisionDay)
+	return !now.Before(start) && now.Before(end)
+}
+
+// sameRelease reports whether two releases name the same date at the same precision
+func sameRelease(a, b model.PlatformRelease) bool {
+	precision := a.DatePrecision()
+	if precision != b.DatePrecision() {
+		return false
+	}
+	return precision == model.DatePrecisionTBD || a.Date.Equal(*b.Date)
+}
+
+// describeRelease formats a release date for a message, e.g. "Mar 3, 2026" or "Q3 2026"
+func describeRelease(release model.PlatformRelease) string {
+	precision := release.DatePrecision()
+	if precision == model.DatePrecisionDay {
+		return release.Date.UTC().Format("Jan 2, 2006")
+	}
+	if precision != model.DatePrecisionTBD && release.Label != "" {
+		return release.Label
+	}
+	return model.ReleaseLabel(release.Date, precision)
+}
+
+func onPlatform(release model.PlatformRelease) string {
+	if release.Platform == "" {
+		return ""
+	}
+	return " on " + release.Platform
+}
+
+// gameURL picks the most useful link for a game
+func gameURL(game *model.Game) string {
+	for _, u := range []string{game.IGDBURL, game.SteamLink(), game.OfficialURL} {
+		if u != "" {
+			return u
+		}
+	}
+	return ""
+}
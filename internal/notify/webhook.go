@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"game-tracker/internal/model"
+)
+
+// Webhook POSTs the message as JSON to any URL
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n *Webhook) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "game-tracker")
+
+	return post(ctx, n.Client, model.ChannelWebhook, req)
+}
+
+// Discord posts the message as an embed through a Discord incoming webhook
+type Discord struct {
+	URL    string
+	Client *http.Client
+}
+
+type discordPayload struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+	Timestamp   string `json:"timestamp,omitempty"`
+}
+
+// Discord's embed limits
+const (
+	discordMaxTitle       = 256
+	discordMaxDescription = 4096
+)
+
+func (n *Discord) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(discordPayload{
+		Username: "Game Tracker",
+		Embeds: []discordEmbed{{
+			Title:       truncate(msg.Title, discordMaxTitle),
+			Description: truncate(msg.Body, discordMaxDescription),
+			URL:         msg.URL,
+			Timestamp:   msg.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode discord payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid discord webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return post(ctx, n.Client, model.ChannelDiscord, req)
+}
+
+// truncate cuts s to at most limit characters
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
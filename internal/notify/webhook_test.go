@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"game-tracker/internal/model"
+)
+
+func testMessage() *Message {
+	return &Message{
+		ID:        "delivery-1",
+		Kind:      model.NotificationReleaseDay,
+		Title:     "Out today: Hollow Knight: Silksong",
+		Body:      "Hollow Knight: Silksong came out today on PC.",
+		URL:       "https://www.igdb.com/games/hollow-knight-silksong",
+		GameID:    "game-1",
+		CreatedAt: time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookSend(t *testing.T) {
+	var got Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	msg := testMessage()
+	n := &Webhook{URL: server.URL, Client: server.Client()}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if got.ID != msg.ID || got.Kind != msg.Kind || got.Title != msg.Title || got.Body != msg.Body ||
+		got.URL != msg.URL || got.GameID != msg.GameID || !got.CreatedAt.Equal(msg.CreatedAt) {
+		t.Errorf("payload = %+v, want %+v", got, *msg)
+	}
+}
+
+func TestWebhookSendStatusError(t *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusNotFound, true},
+		{http.StatusUnauthorized, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusBadGateway, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("internal page contents"))
+			}))
+			defer server.Close()
+
+			n := &Webhook{URL: server.URL, Client: server.Client()}
+			err := n.Send(context.Background(), testMessage())
+
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("Send error = %v, want a *StatusError", err)
+			}
+			if statusErr.StatusCode != tt.status || statusErr.Channel != model.ChannelWebhook {
+				t.Errorf("StatusError = %+v, want webhook HTTP %d", statusErr, tt.status)
+			}
+			if strings.Contains(err.Error(), "internal page") {
+				t.Errorf("error %q echoes the response body", err)
+			}
+			if Permanent(err) != tt.permanent {
+				t.Errorf("Permanent = %v, want %v", Permanent(err), tt.permanent)
+			}
+		})
+	}
+}
+
+func TestDiscordSend(t *testing.T) {
+	var got discordPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	msg := testMessage()
+	n := &Discord{URL: server.URL, Client: server.Client()}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if got.Username != "Game Tracker" {
+		t.Errorf("username = %q, want Game Tracker", got.Username)
+	}
+	if len(got.Embeds) != 1 {
+		t.Fatalf("got %d embeds, want 1", len(got.Embeds))
+	}
+	embed := got.Embeds[0]
+	want := discordEmbed{Title: msg.Title, Description: msg.Body, URL: msg.URL, Timestamp: "2026-03-03T10:00:00Z"}
+	if embed != want {
+		t.Errorf("embed = %+v, want %+v", embed, want)
+	}
+}
+
+func TestDiscordSendTruncatesLongTitles(t *testing.T) {
+	var got discordPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	msg := testMessage()
+	msg.Title = strings.Repeat("é", 300)
+	n := &Discord{URL: server.URL, Client: server.Client()}
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	title := []rune(got.Embeds[0].Title)
+	if len(title) != discordMaxTitle || title[len(title)-1] != '…' {
+		t.Errorf("title has %d characters ending in %q, want %d ending in …", len(title), title[len(title)-1], discordMaxTitle)
+	}
+}
@@ -0,0 +1,366 @@
+// Package recommend ranks a user's Backlog and Break games by how likely they are to be
+// the right thing to play next, explaining each pick
+package recommend
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"game-tracker/internal/model"
+)
+
+// Signals a score is built from
+const (
+	SignalRating   = "rating"   // IGDB critic rating
+	SignalGenres   = "genres"   // Genres of games the user finished or abandoned
+	SignalPlatform = "platform" // Available on the platform the user plays on
+	SignalBreak    = "break"    // Started and put on a break
+	SignalBacklog  = "backlog"  // Time spent in the backlog
+	SignalRecent   = "recent"   // Recently released
+)
+
+// weights is how much each signal counts towards the score
+var weights = map[string]float64{
+	SignalRating:   1.0,
+	SignalGenres:   1.5,
+	SignalPlatform: 0.5,
+	SignalBreak:    0.75,
+	SignalBacklog:  0.5,
+	SignalRecent:   0.75,
+}
+
+// minImpact is the smallest contribution worth explaining
+const minImpact = 0.02
+
+// Genre history tuning
+const (
+	genreHalfLife = 2 * 365 * 24 * time.Hour // A game played this long ago counts half
+	genrePrior    = 2.0                      // Pseudo-count that keeps one or two games from deciding a genre
+	minGenreGames = 2                        // Finished or abandoned games a genre needs before it counts
+)
+
+// surpriseSpread is how strongly surprise mode favours higher scores: a game scoring 1
+// is e^(2*surpriseSpread) times as likely to come up as one scoring -1
+const surpriseSpread = 2.0
+
+// Reason explains one signal's part in a recommendation
+type Reason struct {
+	Signal string  `json:"signal"`
+	Text   string  `json:"text"`
+	Impact float64 `json:"impact"` // Contribution to the score; negative counts against the game
+}
+
+// Recommendation is a ranked game with the reasons behind its score
+type Recommendation struct {
+	Game    *model.Game `json:"game"`
+	Score   float64     `json:"score"` // -1 to 1
+	Reasons []Reason    `json:"reasons"`
+}
+
+// genreStats tallies a genre's finished and abandoned games, weighted by how recently
+// they were played
+type genreStats struct {
+	done, abandoned           float64
+	doneCount, abandonedCount int
+}
+
+// affinity is -1 (always abandoned) to 1 (always finished), pulled towards 0 for genres
+// with few games
+func (s *genreStats) affinity() float64 {
+	return (s.done - s.abandoned) / (s.done + s.abandoned + genrePrior)
+}
+
+// Profile is what the user's history says about their taste
+type Profile struct {
+	genres map[string]*genreStats
+}
+
+// NewProfile builds a profile from the user's history: Done games count for their
+// genres and Abandoned games against them, more so the more recently they were played.
+// Well-rated finished games count extra. Won't Play games were never tried, so they
+// don't count.
+func NewProfile(history []*model.Game, now time.Time) *Profile {
+	profile := &Profile{genres: make(map[string]*genreStats)}
+
+	for _, game := range history {
+		if game.Status != model.StatusDone && game.Status != model.StatusAbandoned {
+			continue
+		}
+
+		weight := 0.5 // Played at some unknown time
+		if game.HasDatePlayed() {
+			age := max(now.Sub(*game.DatePlayed), 0)
+			weight = max(math.Pow(0.5, float64(age)/float64(genreHalfLife)), 0.25)
+		}
+		if game.Status == model.StatusDone && game.UserRating > 0 {
+			switch {
+			case game.UserRating >= 80:
+				weight *= 1.5
+			case game.UserRating < 50:
+				weight *= 0.5
+			}
+		}
+
+		for _, genre := range game.Genres {
+			stats := profile.genres[genre]
+			if stats == nil {
+				stats = &genreStats{}
+				profile.genres[genre] = stats
+			}
+			if game.Status == model.StatusDone {
+				stats.done += weight
+				stats.doneCount++
+			} else {
+				stats.abandoned += weight
+				stats.abandonedCount++
+			}
+		}
+	}
+
+	return profile
+}
+
+// Rank scores the games that are out and returns them best first. Games with
+// PlatformRelease set are dated by it, so the user's platform decides whether they're
+// out; defaultPlatform is the user's settings default.
+func Rank(games []*model.Game, profile *Profile, defaultPlatform string, now time.Time) []Recommendation {
+	recs := make([]Recommendation, 0, len(games))
+	for _, game := range games {
+		if game.Status != model.StatusBacklog && game.Status != model.StatusBreak {
+			continue
+		}
+		if unreleased(release(game, defaultPlatform), now) {
+			continue
+		}
+		recs = append(recs, score(game, profile, defaultPlatform, now))
+	}
+
+	sort.SliceStable(recs, func(i, j int) bool {
+		return recs[i].Score > recs[j].Score
+	})
+	return recs
+}
+
+// Surprise picks up to n of the ranked games at random, favouring higher scores without
+// ruling anything out, in the order drawn
+func Surprise(recs []Recommendation, n int) []Recommendation {
+	// Weighted sampling without replacement (Efraimidis-Spirakis): the n largest keys
+	// u^(1/w) are a weighted random sample
+	type keyed struct {
+		rec Recommendation
+		key float64
+	}
+	drawn := make([]keyed, len(recs))
+	for i, rec := range recs {
+		weight := math.Exp(surpriseSpread * rec.Score)
+		drawn[i] = keyed{rec: rec, key: math.Pow(rand.Float64(), 1/weight)}
+	}
+
+	sort.Slice(drawn, func(i, j int) bool {
+		return drawn[i].key > drawn[j].key
+	})
+
+	picks := make([]Recommendation, 0, min(n, len(drawn)))
+	for _, d := range drawn[:min(n, len(drawn))] {
+		picks = append(picks, d.rec)
+	}
+	return picks
+}
+
+// score combines every signal into a weighted average and keeps the reasons that mattered
+func score(game *model.Game, profile *Profile, defaultPlatform string, now time.Time) Recommendation {
+	rec := Recommendation{Game: game, Reasons: []Reason{}}
+
+	total, sum := 0.0, 0.0
+	add := func(signal string, value float64, text string) {
+		weight := weights[signal]
+		total += weight
+		sum += weight * value
+		if text != "" {
+			rec.Reasons = append(rec.Reasons, Reason{Signal: signal, Text: text, Impact: weight * value})
+		}
+	}
+
+	value, text := ratingSignal(game)
+	add(SignalRating, value, text)
+	value, text = profile.genreSignal(game)
+	add(SignalGenres, value, text)
+	value, text = platformSignal(game, defaultPlatform)
+	add(SignalPlatform, value, text)
+	value, text = breakSignal(game)
+	add(SignalBreak, value, text)
+	value, text = backlogSignal(game, now)
+	add(SignalBacklog, value, text)
+	value, text = recentSignal(release(game, defaultPlatform), now)
+	add(SignalRecent, value, text)
+
+	// Reasons are explained relative to the final score, which is an average
+	reasons := rec.Reasons[:0]
+	for _, reason := range rec.Reasons {
+		reason.Impact = round(reason.Impact / total)
+		if math.Abs(reason.Impact) >= minImpact {
+			reasons = append(reasons, reason)
+		}
+	}
+	sort.SliceStable(reasons, func(i, j int) bool {
+		return math.Abs(reasons[i].Impact) > math.Abs(reasons[j].Impact)
+	})
+	rec.Reasons = reasons
+	rec.Score = round(sum / total)
+
+	return rec
+}
+
+// ratingSignal favours games critics rated above 70, with 90 and up counting fully
+func ratingSignal(game *model.Game) (float64, string) {
+	if game.Rating <= 0 {
+		return 0, ""
+	}
+
+	value := clamp(float64(game.Rating-70) / 20)
+	switch {
+	case value >= 0.25:
+		return value, fmt.Sprintf("Critics rate it %d/100", game.Rating)
+	case value <= -0.25:
+		return value, fmt.Sprintf("Mixed reviews (%d/100)", game.Rating)
+	default:
+		return value, ""
+	}
+}
+
+// genreSignal averages the user's affinity for the game's genres, explained by the
+// genre that swayed it most
+func (p *Profile) genreSignal(game *model.Game) (float64, string) {
+	var total float64
+	var counted int
+	var strongest string
+	var strongestStats *genreStats
+
+	for _, genre := range game.Genres {
+		stats := p.genres[genre]
+		if stats == nil || stats.doneCount+stats.abandonedCount < minGenreGames {
+			continue
+		}
+		affinity := stats.affinity()
+		total += affinity
+		counted++
+		if strongestStats == nil || math.Abs(affinity) > math.Abs(strongestStats.affinity()) {
+			strongest, strongestStats = genre, stats
+		}
+	}
+	if counted == 0 {
+		return 0, ""
+	}
+
+	value := total / float64(counted)
+	played := strongestStats.doneCount + strongestStats.abandonedCount
+	switch {
+	case value > 0 && strongestStats.affinity() > 0:
+		return value, fmt.Sprintf("You finished %d of %d %s games you played", strongestStats.doneCount, played, strongest)
+	case value < 0 && strongestStats.affinity() < 0:
+		return value, fmt.Sprintf("You abandoned %d of %d %s games you played", strongestStats.abandonedCount, played, strongest)
+	default:
+		return value, ""
+	}
+}
+
+// platformSignal favours games on the platform the user plays them on: the game's
+// preferred platform, or the settings default
+func platformSignal(game *model.Game, defaultPlatform string) (float64, string) {
+	if game.PreferredPlatform != "" {
+		return 1, "You plan to play it on " + game.PreferredPlatform
+	}
+	if defaultPlatform == "" || len(game.Platforms) == 0 {
+		return 0, ""
+	}
+
+	for _, platform := range game.Platforms {
+		if platform == defaultPlatform {
+			return 1, "Available on " + defaultPlatform
+		}
+	}
+	return -1, "Not available on " + defaultPlatform
+}
+
+// breakSignal favours games the user started and paused
+func breakSignal(game *model.Game) (float64, string) {
+	if game.Status != model.StatusBreak {
+		return 0, ""
+	}
+	return 1, "You started it and took a break"
+}
+
+// backlogSignal gives games that have waited longer a nudge, growing over three years
+func backlogSignal(game *model.Game, now time.Time) (float64, string) {
+	if game.CreatedAt.IsZero() {
+		return 0, ""
+	}
+
+	years := now.Sub(game.CreatedAt).Hours() / (24 * 365)
+	value := clamp(years / 3)
+	switch {
+	case years >= 2:
+		return value, fmt.Sprintf("In your backlog for %d years", int(years))
+	case years >= 1:
+		return value, "In your backlog for over a year"
+	default:
+		return value, ""
+	}
+}
+
+// recentSignal favours games released in the last year, newest most
+func recentSignal(release model.PlatformRelease, now time.Time) (float64, string) {
+	if release.DatePrecision() == model.DatePrecisionTBD {
+		return 0, ""
+	}
+
+	months := now.Sub(*release.Date).Hours() / (24 * 30)
+	if months < 0 || months >= 12 {
+		return 0, ""
+	}
+
+	value := 1 - months/12
+	switch {
+	case months < 1:
+		return value, "Released in the last month"
+	case release.DatePrecision() == model.DatePrecisionDay && months < 2:
+		return value, "Released a month ago"
+	case release.DatePrecision() == model.DatePrecisionDay:
+		return value, fmt.Sprintf("Released %d months ago", int(months))
+	default:
+		return value, "Released " + release.Label
+	}
+}
+
+// release is the release a game is dated by for the user
+func release(game *model.Game, defaultPlatform string) model.PlatformRelease {
+	if game.PlatformRelease != nil {
+		return *game.PlatformRelease
+	}
+	return game.ReleaseFor(defaultPlatform)
+}
+
+// unreleased reports whether a dated release is still to come. Imprecise dates count as
+// unreleased until their period ends; undated games can't be told apart from old games
+// IGDB has no date for, so they stay in.
+func unreleased(release model.PlatformRelease, now time.Time) bool {
+	switch release.DatePrecision() {
+	case model.DatePrecisionTBD:
+		return false
+	case model.DatePrecisionDay:
+		return release.Date.After(now)
+	default:
+		return release.PeriodEnd().After(now)
+	}
+}
+
+func clamp(value float64) float64 {
+	return max(-1, min(1, value))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
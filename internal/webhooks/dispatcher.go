@@ -0,0 +1,381 @@
+// Package webhooks delivers game change events to users' webhook subscriptions: signed
+// with the subscription's secret, sent in the background and retried with backoff
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/netip"
+	"sync/atomic"
+	"time"
+
+	"game-tracker/internal/database"
+	"game-tracker/internal/model"
+	"game-tracker/internal/netguard"
+)
+
+// Options tunes delivery
+type Options struct {
+	Senders         int            // Deliveries sent in parallel
+	MaxAttempts     int            // Attempts per delivery before giving up
+	Timeout         time.Duration  // Upper bound for a single attempt
+	PollInterval    time.Duration  // How often due retries are picked up
+	AllowedNetworks []netip.Prefix // Private networks subscriptions may reach, e.g. for local stand-ins
+}
+
+// Retry delays for failed deliveries
+const (
+	retryBase = 1 * time.Minute
+	retryMax  = 6 * time.Hour
+)
+
+// Delivery queue sizing
+const (
+	queueSize = 256
+	pollBatch = 100
+)
+
+// maxDrainBytes is how much of a response is read so the connection can be reused
+const maxDrainBytes = 64 << 10
+
+// Envelope is the JSON body of every webhook request
+type Envelope struct {
+	ID        string             `json:"id"` // Event ID; the same for every subscription and every retry
+	Type      model.WebhookEvent `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+	Data      any                `json:"data"`
+}
+
+// Dispatcher records deliveries and sends them in the background. Every attempt is
+// claimed in Firestore first, so several instances can share the queue.
+type Dispatcher struct {
+	db       *database.Client
+	guard    *netguard.Guard
+	client   *http.Client
+	opts     Options
+	queue    chan string  // Delivery IDs ready to send
+	inflight atomic.Int64 // Queued or sending
+}
+
+func NewDispatcher(db *database.Client, opts Options) *Dispatcher {
+	if opts.Senders < 1 {
+		opts.Senders = 4
+	}
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 8
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 30 * time.Second
+	}
+
+	guard := netguard.New(opts.AllowedNetworks)
+	client := guard.Client(opts.Timeout)
+	// A redirect usually means a misconfigured URL; following it would turn the POST
+	// into a GET
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &Dispatcher{
+		db:     db,
+		guard:  guard,
+		opts:   opts,
+		client: client,
+		queue:  make(chan string, queueSize),
+	}
+}
+
+// CheckURL checks that a subscription URL is http(s) and doesn't point at a private or
+// local address. Connections are checked again when sending, after DNS resolution.
+func (d *Dispatcher) CheckURL(raw string) error {
+	return d.guard.CheckURL(raw)
+}
+
+// Start runs the senders and the retry poller until ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	log.Printf("Starting webhook dispatcher (%d senders)", d.opts.Senders)
+
+	for range d.opts.Senders {
+		go d.send(ctx)
+	}
+	go d.poll(ctx)
+}
+
+// Drain waits until every queued delivery has been attempted or ctx is done, for
+// processes about to exit. Deliveries left pending are retried by any running instance.
+func (d *Dispatcher) Drain(ctx context.Context) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for d.inflight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Emit records a delivery of the event to each of the user's active subscriptions that
+// wants it and queues them for sending. data becomes the envelope's data field.
+func (d *Dispatcher) Emit(ctx context.Context, userID string, event model.WebhookEvent, data any) error {
+	return d.EmitEach(ctx, userID, event, []any{data})
+}
+
+// EmitEach emits one event of the given type per data item
+func (d *Dispatcher) EmitEach(ctx context.Context, userID string, event model.WebhookEvent, data []any) error {
+	subs, err := d.db.GetActiveWebhookSubscriptions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var targets []*model.WebhookSubscription
+	for _, sub := range subs {
+		if sub.Wants(event) {
+			targets = append(targets, sub)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	for _, item := range data {
+		payload, err := newPayload(event, item)
+		if err != nil {
+			return err
+		}
+		for _, sub := range targets {
+			if _, err := d.deliver(ctx, sub, payload); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Ping queues a ping event to the subscription, whether or not it is active
+func (d *Dispatcher) Ping(ctx context.Context, sub *model.WebhookSubscription) (*model.WebhookDelivery, error) {
+	payload, err := newPayload(model.EventPing, map[string]string{"subscription_id": sub.ID})
+	if err != nil {
+		return nil, err
+	}
+	return d.deliver(ctx, sub, payload)
+}
+
+// Redeliver queues a new delivery of an earlier delivery's event, with the same payload
+func (d *Dispatcher) Redeliver(ctx context.Context, sub *model.WebhookSubscription, delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	return d.deliver(ctx, sub, &payload{
+		eventID: delivery.EventID,
+		event:   delivery.Event,
+		body:    delivery.Payload,
+	})
+}
+
+// payload is an encoded event, shared by the deliveries to each subscription
+type payload struct {
+	eventID string
+	event   model.WebhookEvent
+	body    string
+}
+
+func newPayload(event model.WebhookEvent, data any) (*payload, error) {
+	envelope := Envelope{
+		ID:        "evt_" + randomHex(16),
+		Type:      event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", event, err)
+	}
+
+	return &payload{eventID: envelope.ID, event: event, body: string(body)}, nil
+}
+
+// deliver records a pending delivery and queues it
+func (d *Dispatcher) deliver(ctx context.Context, sub *model.WebhookSubscription, p *payload) (*model.WebhookDelivery, error) {
+	now := time.Now()
+	delivery := &model.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		EventID:        p.eventID,
+		Event:          p.event,
+		Payload:        p.body,
+		Status:         model.DeliveryPending,
+		NextAttemptAt:  &now,
+		CreatedAt:      now,
+	}
+
+	if err := d.db.SaveWebhookDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	d.enqueue(delivery.ID)
+	return delivery, nil
+}
+
+// enqueue hands a delivery to the senders; when the queue is full it stays pending and
+// the poller picks it up
+func (d *Dispatcher) enqueue(id string) {
+	d.inflight.Add(1)
+	select {
+	case d.queue <- id:
+	default:
+		d.inflight.Add(-1)
+	}
+}
+
+// poll queues due retries, and deliveries that didn't fit in the queue, every interval
+func (d *Dispatcher) poll(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		ids, err := d.db.GetDueWebhookDeliveries(ctx, time.Now(), pollBatch)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("ERROR: Failed to fetch due webhook deliveries: %v", err)
+		}
+		for _, id := range ids {
+			d.enqueue(id)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-d.queue:
+			d.attempt(ctx, id)
+			d.inflight.Add(-1)
+		}
+	}
+}
+
+// attempt claims a delivery and sends it once, recording the outcome and scheduling a
+// retry with exponential backoff on failure
+func (d *Dispatcher) attempt(ctx context.Context, id string) {
+	now := time.Now()
+	// The claim outlives the attempt, so a crashed sender's delivery is retried later
+	delivery, err := d.db.ClaimWebhookDelivery(ctx, id, now, now.Add(d.opts.Timeout+time.Minute))
+	if err != nil {
+		log.Printf("ERROR: Failed to claim webhook delivery %s: %v", id, err)
+		return
+	}
+	if delivery == nil {
+		return
+	}
+
+	sub, err := d.db.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch webhook subscription %s: %v", delivery.SubscriptionID, err)
+		return
+	}
+
+	switch {
+	case sub == nil:
+		d.finish(ctx, delivery, 0, fmt.Errorf("subscription was deleted"), true)
+	case !sub.Active && delivery.Event != model.EventPing:
+		d.finish(ctx, delivery, 0, fmt.Errorf("subscription is disabled"), true)
+	default:
+		status, err := d.post(ctx, sub, delivery)
+		d.finish(ctx, delivery, status, err, false)
+	}
+}
+
+// post signs and sends the delivery's payload, returning the response status
+func (d *Dispatcher) post(ctx context.Context, sub *model.WebhookSubscription, delivery *model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "game-tracker-webhooks")
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The body isn't kept: the delivery log is shown to the user, and the body could be
+	// anything the URL serves
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+}
+
+// finish records an attempt's outcome. final gives up without retrying.
+func (d *Dispatcher) finish(ctx context.Context, delivery *model.WebhookDelivery, status int, err error, final bool) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+
+	switch {
+	case err == nil:
+		delivery.Status = model.DeliverySent
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	case final || delivery.Attempts >= d.opts.MaxAttempts:
+		delivery.Status = model.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = err.Error()
+		log.Printf("Giving up on webhook delivery %s (%s) after %d attempts: %v", delivery.ID, delivery.Event, delivery.Attempts, err)
+	default:
+		next := now.Add(retryBackoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = err.Error()
+	}
+
+	// Record the outcome even when shutting down mid-attempt
+	if err := d.db.SaveWebhookDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		log.Printf("ERROR: Failed to record webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// retryBackoff is the wait after the nth failed attempt: doubling from retryBase, capped
+// at retryMax
+func retryBackoff(attempts int) time.Duration {
+	backoff := retryBase
+	for i := 1; i < attempts && backoff < retryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, retryMax)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
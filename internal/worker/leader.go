@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"game-tracker/internal/database"
+)
+
+// syncLeaseName is the lock document guarding the background sync
+const syncLeaseName = "background-sync"
+
+// errLeaseLost is the cause of a leader context cancelled because the lease was lost
+var errLeaseLost = errors.New("lost the sync lease")
+
+// election keeps a database lease so only one instance runs the background sync.
+// Every run goes through it: scheduled runs and queued admin runs start from the
+// leader's sync loop, and one-shot cron runs take the lease for their duration. The
+// leader renews the lease every third of its TTL; if the leader dies, the lease
+// expires and another instance takes over on its next attempt.
+type election struct {
+	db     *database.Client
+	name   string
+	holder string
+	ttl    time.Duration
+}
+
+func newElection(db *database.Client, name string, ttl time.Duration) *election {
+	return &election{
+		db:     db,
+		name:   name,
+		holder: instanceID(),
+		ttl:    ttl,
+	}
+}
+
+// instanceID identifies this process as a lease holder
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
+
+// run campaigns for the lease until ctx is cancelled, calling lead whenever this
+// instance becomes leader. lead's context is cancelled with errLeaseLost when leadership
+// is lost.
+func (e *election) run(ctx context.Context, lead func(ctx context.Context)) {
+	retry := time.NewTicker(e.ttl / 3)
+	defer retry.Stop()
+
+	for {
+		acquired, err := e.db.AcquireLease(ctx, e.name, e.holder, e.ttl)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("ERROR: %v", err)
+		}
+		if acquired {
+			log.Printf("Acquired %s lease as %s", e.name, e.holder)
+			e.hold(ctx, lead)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-retry.C:
+		}
+	}
+}
+
+// once runs lead if the lease is free, holding it meanwhile, and reports whether lead ran
+func (e *election) once(ctx context.Context, lead func(ctx context.Context)) (bool, error) {
+	acquired, err := e.db.AcquireLease(ctx, e.name, e.holder, e.ttl)
+	if err != nil {
+		return false, err
+	}
+	if !acquired {
+		return false, nil
+	}
+
+	e.hold(ctx, lead)
+	return true, nil
+}
+
+// hold runs lead while renewing the lease, then releases it. Transient renewal errors
+// are tolerated while the last successful renewal is still comfortably valid.
+func (e *election) hold(ctx context.Context, lead func(ctx context.Context)) {
+	leaderCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lead(leaderCtx)
+	}()
+
+	renew := time.NewTicker(e.ttl / 3)
+	defer renew.Stop()
+	expires := time.Now().Add(e.ttl)
+
+	for {
+		select {
+		case <-done:
+			e.release(ctx)
+			return
+		case <-ctx.Done():
+			<-done
+			e.release(ctx)
+			return
+		case <-renew.C:
+			acquired, err := e.db.AcquireLease(ctx, e.name, e.holder, e.ttl)
+			switch {
+			case acquired:
+				expires = time.Now().Add(e.ttl)
+				continue
+			case err != nil && time.Until(expires) > e.ttl/3:
+				log.Printf("ERROR: Failed to renew %s lease, retrying: %v", e.name, err)
+				continue
+			case err != nil:
+				log.Printf("ERROR: Lost %s lease: %v", e.name, err)
+			default:
+				log.Printf("Lost %s lease to another instance", e.name)
+			}
+
+			cancel(errLeaseLost)
+			<-done
+			return
+		}
+	}
+}
+
+func (e *election) release(ctx context.Context) {
+	if err := e.db.ReleaseLease(context.WithoutCancel(ctx), e.name, e.holder); err != nil {
+		log.Printf("ERROR: %v", err)
+		return
+	}
+	log.Printf("Released %s lease", e.name)
+}
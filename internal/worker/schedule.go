@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"time"
+
+	"game-tracker/internal/model"
+)
+
+// Refresh windows for the adaptive sync schedule
+const (
+	upcomingWindow = 30 * 24 * time.Hour // Games releasing this soon are refreshed every run
+	recentWindow   = 7 * 24 * time.Hour  // ...as are games released this recently (patches, rating changes)
+	settledAge     = 180 * 24 * time.Hour
+
+	defaultRefresh = 24 * time.Hour
+	undatedRefresh = 6 * time.Hour      // Unannounced release dates tend to appear without warning
+	settledRefresh = 7 * 24 * time.Hour // Finished games released long ago rarely change
+)
+
+// nextSyncAt decides when a game should next be refreshed from IGDB.
+// No game is scheduled sooner than the next run (interval from now).
+func nextSyncAt(game *model.Game, now time.Time, interval time.Duration) time.Time {
+	return now.Add(max(refreshPeriod(game, now), interval))
+}
+
+func refreshPeriod(game *model.Game, now time.Time) time.Duration {
+	if !game.HasReleaseDate() {
+		if isFinished(game.Status) {
+			return settledRefresh
+		}
+		return undatedRefresh
+	}
+
+	sinceRelease := now.Sub(*game.ReleaseDate)
+	switch {
+	case sinceRelease >= -upcomingWindow && sinceRelease <= recentWindow:
+		return 0
+	case sinceRelease > settledAge && isFinished(game.Status):
+		return settledRefresh
+	default:
+		return defaultRefresh
+	}
+}
+
+// failureBackoff is the wait after the nth consecutive failure: one interval, doubling
+// with each further failure, capped at the settled refresh period
+func failureBackoff(failures int, interval time.Duration) time.Duration {
+	backoff := interval
+	for i := 1; i < failures && backoff < settledRefresh; i++ {
+		backoff *= 2
+	}
+	return min(backoff, max(settledRefresh, interval))
+}
+
+// Retry delays for games the matcher couldn't match on its own
+const (
+	matchRetryBase = 24 * time.Hour // IGDB adds and renames games slowly
+	matchRetryMax  = 30 * 24 * time.Hour
+)
+
+// matchBackoff is the wait after the nth match attempt that found no single match: a day,
+// doubling with each further attempt, capped at a month
+func matchBackoff(attempts int) time.Duration {
+	backoff := matchRetryBase
+	for i := 1; i < attempts && backoff < matchRetryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, matchRetryMax)
+}
+
+// isFinished reports whether the user is done with the game one way or another
+func isFinished(status model.GameStatus) bool {
+	return status == model.StatusDone || status == model.StatusAbandoned || status == model.StatusWontPlay
+}
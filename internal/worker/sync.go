@@ -2,182 +2,647 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"game-tracker/internal/api"
 	"game-tracker/internal/database"
 	"game-tracker/internal/igdb"
+	"game-tracker/internal/matcher"
 	"game-tracker/internal/model"
+	"game-tracker/internal/notify"
+	"game-tracker/internal/webhooks"
 )
 
-// StartBackgroundSync starts a background goroutine that syncs game metadata from IGDB every 15 minutes
-func StartBackgroundSync(ctx context.Context, db *database.Client, igdbClient *igdb.Client) {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
+// Options tunes the background sync
+type Options struct {
+	Interval       time.Duration        // Time between runs; each game is refreshed on its own schedule within that
+	Concurrency    int                  // Games processed in parallel; IGDB's rate limit is shared through the client
+	GameTimeout    time.Duration        // Upper bound for syncing or matching a single game
+	MaxFailures    int                  // Consecutive failures before a game's sync is disabled
+	LeaseTTL       time.Duration        // Leader lease lifetime; zero runs the sync without leader election
+	MatchThreshold float64              // Confidence needed to match a game automatically; zero uses the matcher default
+	Notifier       *notify.Service      // Sends release notifications and retries failed ones; nil disables them
+	Webhooks       *webhooks.Dispatcher // Receives game.metadata_updated events; nil disables them
+}
+
+type Worker struct {
+	db         *database.Client
+	igdbClient *igdb.Client
+	matcher    *matcher.Matcher
+	opts       Options
+	running    atomic.Bool // Set while a run executes; runs on this instance never overlap
+	election   *election   // Nil without leader election
+}
+
+func New(db *database.Client, igdbClient *igdb.Client, opts Options) *Worker {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 1 * time.Hour
+	}
+	if opts.GameTimeout <= 0 {
+		opts.GameTimeout = 30 * time.Second
+	}
+	if opts.MaxFailures < 1 {
+		opts.MaxFailures = 10
+	}
 
-	log.Println("Starting background sync worker (15 minute interval)")
+	w := &Worker{
+		db:         db,
+		igdbClient: igdbClient,
+		matcher:    matcher.New(db, igdbClient.Search, igdbClient.SearchAlternativeNames, opts.MatchThreshold),
+		opts:       opts,
+	}
+	if opts.LeaseTTL > 0 {
+		w.election = newElection(db, syncLeaseName, opts.LeaseTTL)
+	}
+	return w
+}
+
+// maxRecordedGameErrors caps the per-game errors kept on a run record, so an IGDB
+// outage can't push the document past Firestore's size limit
+const maxRecordedGameErrors = 100
+
+// collector lets pool goroutines record outcomes into a shared result
+type collector struct {
+	mu     sync.Mutex
+	result model.SyncResult
+}
+
+func (c *collector) add(fn func(r *model.SyncResult)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(&c.result)
+}
+
+func (c *collector) fail(game *model.Game, err error) {
+	c.add(func(r *model.SyncResult) {
+		r.Errors++
+		if len(r.GameErrors) < maxRecordedGameErrors {
+			r.GameErrors = append(r.GameErrors, model.SyncGameError{GameID: game.ID, Title: game.Title, Error: err.Error()})
+		}
+	})
+}
+
+// StartBackgroundSync runs a sync every interval until ctx is cancelled. With leader
+// election, only the instance holding the sync lease runs; the others wait to take over.
+func (w *Worker) StartBackgroundSync(ctx context.Context) {
+	log.Printf("Starting background sync worker (%s interval, %d concurrent games)", w.opts.Interval, w.opts.Concurrency)
+
+	if w.election == nil {
+		log.Println("Warning: Leader election is disabled; run a single sync instance")
+		w.syncLoop(ctx)
+	} else {
+		log.Printf("Waiting for %s lease (TTL %s)", w.election.name, w.election.ttl)
+		w.election.run(ctx, w.syncLoop)
+	}
+
+	log.Println("Background sync worker stopped")
+}
+
+// queuePollInterval is how often the sync loop looks for runs requested through the
+// admin API
+const queuePollInterval = 15 * time.Second
+
+// syncLoop runs the scheduled sync every interval and starts queued admin runs. With
+// leader election it only runs on the lease holder, and ctx ends when the lease is lost.
+func (w *Worker) syncLoop(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	queue := time.NewTicker(queuePollInterval)
+	defer queue.Stop()
 
 	// Run initial sync
-	syncGames(ctx, db, igdbClient)
+	w.runOnce(ctx)
+	w.runQueued(ctx)
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Background sync worker stopped")
 			return
 		case <-ticker.C:
-			syncGames(ctx, db, igdbClient)
+			w.runOnce(ctx)
+		case <-queue.C:
+			w.runQueued(ctx)
 		}
 	}
 }
 
-func syncGames(ctx context.Context, db *database.Client, igdbClient *igdb.Client) {
-	log.Println("Starting background game metadata sync...")
+// runOnce syncs matched games that are due, then tries to match unmatched ones.
+// It returns nil without doing anything when another run is in progress.
+func (w *Worker) runOnce(ctx context.Context) *model.SyncRun {
+	if !w.running.CompareAndSwap(false, true) {
+		log.Println("Skipping scheduled sync: another run is in progress")
+		return nil
+	}
+	defer w.running.Store(false)
+
+	run := w.newRun(ctx, model.SyncTriggerSchedule, model.SyncScope{}, "")
+	w.execute(ctx, run)
+	return run
+}
+
+// RunOnceAsLeader runs a single sync, then any queued admin runs, for one-shot runs from
+// cron. With leader election it only runs when no other instance holds the sync lease.
+// It returns a nil run when another instance or run is already syncing.
+func (w *Worker) RunOnceAsLeader(ctx context.Context) (*model.SyncRun, error) {
+	lead := func(ctx context.Context) *model.SyncRun {
+		run := w.runOnce(ctx)
+		w.runQueued(ctx)
+		return run
+	}
+
+	if w.election == nil {
+		return lead(ctx), nil
+	}
 
-	syncMatchedGames(ctx, db, igdbClient)
-	matchUnmatchedGames(ctx, db, igdbClient)
+	var run *model.SyncRun
+	_, err := w.election.once(ctx, func(ctx context.Context) {
+		run = lead(ctx)
+	})
+	return run, err
 }
 
-func syncMatchedGames(ctx context.Context, db *database.Client, igdbClient *igdb.Client) {
-	games, err := db.GetGamesWithIGDBID(ctx)
+// QueueSync records a run for the given scope and returns it in the queued state. The
+// run is started by the instance holding the sync lease (or the only instance, without
+// leader election) within queuePollInterval, so it never overlaps the scheduled sync.
+// Scoped runs refresh every matched game in scope, whether due or not and including
+// games whose sync was disabled. Only one run waits in the queue at a time.
+func (w *Worker) QueueSync(ctx context.Context, scope model.SyncScope, requestedBy string) (*model.SyncRun, error) {
+	queued, err := w.db.GetQueuedSyncRuns(ctx)
 	if err != nil {
-		log.Printf("ERROR: Failed to fetch games for sync: %v", err)
-		return
+		return nil, err
+	}
+	if len(queued) > 0 {
+		return nil, api.ErrSyncQueued
 	}
 
-	log.Printf("Found %d matched games to sync", len(games))
+	run := &model.SyncRun{
+		Trigger:     model.SyncTriggerAdmin,
+		RequestedBy: requestedBy,
+		Scope:       scope,
+		Status:      model.SyncRunQueued,
+		StartedAt:   time.Now(),
+	}
+	if err := w.db.SaveSyncRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
 
-	successCount := 0
-	errorCount := 0
+// runQueued starts the queued admin runs one after another, claiming each first so a
+// run starts once even if leadership changes hands
+func (w *Worker) runQueued(ctx context.Context) {
+	if !w.running.CompareAndSwap(false, true) {
+		return
+	}
+	defer w.running.Store(false)
 
-	for _, game := range games {
-		if game.IGDBID == 0 {
-			continue
+	queued, err := w.db.GetQueuedSyncRuns(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("ERROR: Failed to fetch queued sync runs: %v", err)
 		}
+		return
+	}
 
-		igdbGame, err := igdbClient.GetGameByID(game.IGDBID)
+	for _, q := range queued {
+		if ctx.Err() != nil {
+			return
+		}
+		run, err := w.db.ClaimSyncRun(ctx, q.ID, time.Now())
 		if err != nil {
-			log.Printf("ERROR: Failed to fetch IGDB data for game '%s' (ID: %d): %v", game.Title, game.IGDBID, err)
+			log.Printf("ERROR: %v", err)
+			continue
+		}
+		if run == nil {
+			continue
+		}
+		w.execute(ctx, run)
+	}
+}
 
-			game.LastSyncError = err.Error()
-			if saveErr := db.SaveGame(ctx, game); saveErr != nil {
-				log.Printf("ERROR: Failed to update sync error for game '%s': %v", game.Title, saveErr)
+func (w *Worker) newRun(ctx context.Context, trigger model.SyncTrigger, scope model.SyncScope, requestedBy string) *model.SyncRun {
+	run := &model.SyncRun{
+		Trigger:     trigger,
+		RequestedBy: requestedBy,
+		Scope:       scope,
+		Status:      model.SyncRunRunning,
+		StartedAt:   time.Now(),
+	}
+	if err := w.db.SaveSyncRun(ctx, run); err != nil {
+		log.Printf("ERROR: Failed to record sync run: %v", err)
+	}
+	return run
+}
+
+// execute performs the run and records its outcome
+func (w *Worker) execute(ctx context.Context, run *model.SyncRun) {
+	log.Printf("Starting %s game metadata sync%s...", run.Trigger, describeScope(run.Scope))
+
+	c := &collector{}
+	matched, unmatched, err := w.selectGames(ctx, run.Scope)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch games for sync: %v", err)
+		run.Status = model.SyncRunFailed
+		run.Error = err.Error()
+	} else {
+		w.syncMatchedGames(ctx, matched, c)
+		w.matchUnmatchedGames(ctx, unmatched, c)
+		if ctx.Err() != nil {
+			// Games cut off mid-sync aren't counted as errors, so the status has to say it
+			log.Printf("Background sync interrupted: %v", context.Cause(ctx))
+			run.Status = model.SyncRunInterrupted
+			run.Error = fmt.Sprintf("interrupted: %v", context.Cause(ctx))
+		} else {
+			// Release days are checked across every user, so scoped admin runs leave them
+			// to the scheduled runs
+			if run.Scope == (model.SyncScope{}) {
+				w.notifyReleaseDays(ctx)
 			}
-			errorCount++
-			continue
+			w.retryNotifications(ctx)
+			run.Status = model.SyncRunCompleted
 		}
+	}
 
-		updated := api.EnrichGameFromIGDB(game, igdbGame, true)
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.SyncResult = c.result
 
-		if updated {
-			game.LastSyncError = ""
+	r := run.SyncResult
+	log.Printf("Background sync finished in %s: %d synced, %d unchanged, %d errors, %d auto-matched, %d multiple, %d no match",
+		finished.Sub(run.StartedAt).Round(time.Second), r.Synced, r.Unchanged, r.Errors, r.Matched, r.Multiple, r.NoMatch)
 
-			if err := db.SaveGame(ctx, game); err != nil {
-				log.Printf("ERROR: Failed to save updated game '%s': %v", game.Title, err)
-				errorCount++
-				continue
+	// Record the outcome even when the run was cut short by shutdown
+	if err := w.db.SaveSyncRun(context.WithoutCancel(ctx), run); err != nil {
+		log.Printf("ERROR: Failed to record sync run: %v", err)
+	}
+}
+
+func describeScope(scope model.SyncScope) string {
+	switch {
+	case scope.GameID != "":
+		return " for game " + scope.GameID
+	case scope.UserID != "":
+		return " for user " + scope.UserID
+	default:
+		return ""
+	}
+}
+
+// selectGames returns the matched games to refresh and the unmatched games to match.
+// Unscoped runs only pick games that are due.
+func (w *Worker) selectGames(ctx context.Context, scope model.SyncScope) (matched, unmatched []*model.Game, err error) {
+	var games []*model.Game
+
+	switch {
+	case scope.GameID != "":
+		game, err := w.db.GetGame(ctx, scope.GameID)
+		if err != nil {
+			return nil, nil, err
+		}
+		games = []*model.Game{game}
+	case scope.UserID != "":
+		games, err = w.db.GetGames(ctx, scope.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		matched, err = w.db.GetGamesDueForSync(ctx, time.Now())
+		if err != nil {
+			return nil, nil, err
+		}
+		unmatched, err = w.db.GetUnmatchedGames(ctx, time.Now())
+		if err != nil {
+			return nil, nil, err
+		}
+		return matched, unmatched, nil
+	}
+
+	for _, game := range games {
+		if game.IGDBID > 0 {
+			matched = append(matched, game)
+		} else {
+			unmatched = append(unmatched, game)
+		}
+	}
+	return matched, unmatched, nil
+}
+
+// runPool calls fn for every item with at most concurrency calls in flight,
+// each under its own timeout. It returns once all calls finish or ctx is cancelled.
+func runPool[T any](ctx context.Context, items []T, concurrency int, timeout time.Duration, fn func(ctx context.Context, item T)) {
+	jobs := make(chan T)
+	var wg sync.WaitGroup
+
+	for range min(concurrency, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				itemCtx, cancel := context.WithTimeout(ctx, timeout)
+				fn(itemCtx, item)
+				cancel()
 			}
+		}()
+	}
 
-			log.Printf("Successfully synced game: %s", game.Title)
-			successCount++
+feed:
+	for _, item := range items {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- item:
 		}
 	}
+	close(jobs)
+
+	wg.Wait()
+}
+
+func (w *Worker) syncMatchedGames(ctx context.Context, games []*model.Game, c *collector) {
+	log.Printf("Found %d matched games to sync", len(games))
+
+	timeToBeats := w.fetchTimeToBeats(ctx, games)
+
+	runPool(ctx, games, w.opts.Concurrency, w.opts.GameTimeout, func(ctx context.Context, game *model.Game) {
+		w.syncGame(ctx, game, timeToBeats[game.IGDBID], c)
+	})
+}
+
+// fetchTimeToBeats looks up the playtime estimates for every game in the run in batched
+// requests, rather than one extra request per game. They're a nice-to-have, so a failed
+// lookup leaves the games' estimates as they are.
+func (w *Worker) fetchTimeToBeats(ctx context.Context, games []*model.Game) map[int]*igdb.TimeToBeat {
+	seen := make(map[int]bool, len(games))
+	ids := make([]int, 0, len(games))
+	for _, game := range games {
+		if game.IGDBID > 0 && !seen[game.IGDBID] {
+			seen[game.IGDBID] = true
+			ids = append(ids, game.IGDBID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	timeToBeats, err := w.igdbClient.GetTimeToBeats(ctx, ids)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch time to beat: %v", err)
+		return nil
+	}
+	return timeToBeats
+}
+
+func (w *Worker) syncGame(ctx context.Context, game *model.Game, timeToBeat *igdb.TimeToBeat, c *collector) {
+	if game.IGDBID == 0 {
+		return
+	}
+
+	updated, err := w.refreshGame(ctx, game, timeToBeat)
+	switch {
+	case errors.Is(err, context.Canceled):
+		// Shutting down isn't the game's fault
+	case err != nil:
+		c.fail(game, err)
+	case updated:
+		c.add(func(r *model.SyncResult) { r.Synced++ })
+	default:
+		c.add(func(r *model.SyncResult) { r.Unchanged++ })
+	}
+}
+
+// RefreshGame re-fetches a matched game from IGDB and saves any changes, with the same
+// failure tracking and rescheduling as a scheduled sync. It reports whether any
+// metadata changed; the game is updated in place. Time to beat is left to the
+// scheduled sync, which fetches it for all games in one go.
+func (w *Worker) RefreshGame(ctx context.Context, game *model.Game) (bool, error) {
+	return w.refreshGame(ctx, game, nil)
+}
+
+// refreshGame is RefreshGame with the game's time to beat, when the run fetched it
+func (w *Worker) refreshGame(ctx context.Context, game *model.Game, timeToBeat *igdb.TimeToBeat) (bool, error) {
+	before := *game
+
+	igdbGame, err := w.igdbClient.GetGameByID(ctx, game.IGDBID)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch IGDB data for game '%s' (ID: %d): %v", game.Title, game.IGDBID, err)
+
+		if errors.Is(err, context.Canceled) {
+			return false, err
+		}
+
+		w.recordFailure(game, err)
+		// Record the error even when the game's own timeout expired
+		if saveErr := w.db.UpdateSyncState(context.WithoutCancel(ctx), game); saveErr != nil {
+			log.Printf("ERROR: Failed to update sync error for game '%s': %v", game.Title, saveErr)
+		}
+		return false, err
+	}
+
+	igdbGame.TimeToBeat = timeToBeat
+
+	// A successful fetch ends any failure streak; clear it first so recovering alone
+	// doesn't count as a metadata change
+	recovered := game.SyncFailures > 0 || game.LastSyncError != "" || game.SyncDisabled
+	game.SyncFailures = 0
+	game.SyncDisabled = false
+	game.LastSyncError = ""
+
+	updated := api.EnrichGameFromIGDB(game, igdbGame, true)
+
+	// Schedule from the refreshed metadata, so a newly announced release date takes effect
+	next := nextSyncAt(game, time.Now(), w.opts.Interval)
+	game.NextSyncAt = &next
+
+	if !updated {
+		if err := w.db.UpdateSyncState(ctx, game); err != nil {
+			log.Printf("ERROR: Failed to schedule next sync for '%s': %v", game.Title, err)
+		}
+		if recovered {
+			log.Printf("Sync recovered for game: %s", game.Title)
+		}
+		return false, nil
+	}
+
+	if err := w.db.SaveGame(ctx, game); err != nil {
+		log.Printf("ERROR: Failed to save updated game '%s': %v", game.Title, err)
+		return false, err
+	}
+
+	log.Printf("Successfully synced game: %s", game.Title)
+	api.EmitGameEvent(ctx, w.opts.Webhooks, model.EventGameMetadataUpdated, api.GameEventData{Game: game, Changes: api.DiffGames(&before, game)})
+	w.notifyReleaseChange(ctx, &before, game)
+	return true, nil
+}
+
+// notifyReleaseChange tells the owner when a refresh moved a game's release date
+func (w *Worker) notifyReleaseChange(ctx context.Context, before, after *model.Game) {
+	if w.opts.Notifier == nil {
+		return
+	}
+	if err := w.opts.Notifier.ReleaseDateChanged(ctx, before, after); err != nil {
+		log.Printf("ERROR: Failed to send release date notification for '%s': %v", after.Title, err)
+	}
+}
 
-	log.Printf("Background sync complete: %d successful, %d errors", successCount, errorCount)
+// notifyReleaseDays tells owners about backlog games that came out recently, whether or
+// not they were due for a refresh
+func (w *Worker) notifyReleaseDays(ctx context.Context) {
+	if w.opts.Notifier == nil || ctx.Err() != nil {
+		return
+	}
+	if err := w.opts.Notifier.ReleaseDays(ctx, time.Now()); err != nil && ctx.Err() == nil {
+		log.Printf("ERROR: Failed to send release day notifications: %v", err)
+	}
 }
 
-func matchUnmatchedGames(ctx context.Context, db *database.Client, igdbClient *igdb.Client) {
-	games, err := db.GetUnmatchedGames(ctx)
+// retryNotifications retries notification deliveries that failed earlier and are due
+func (w *Worker) retryNotifications(ctx context.Context) {
+	if w.opts.Notifier == nil || ctx.Err() != nil {
+		return
+	}
+	sent, failed, err := w.opts.Notifier.RetryDue(ctx, time.Now())
 	if err != nil {
-		log.Printf("ERROR: Failed to fetch unmatched games: %v", err)
+		log.Printf("ERROR: Failed to retry notifications: %v", err)
+		return
+	}
+	if sent > 0 || failed > 0 {
+		log.Printf("Retried notifications: %d sent, %d given up", sent, failed)
+	}
+}
+
+// recordFailure counts a failed sync and backs the game off exponentially, disabling
+// its sync once MaxFailures consecutive attempts have failed
+func (w *Worker) recordFailure(game *model.Game, err error) {
+	game.SyncFailures++
+	game.LastSyncError = err.Error()
+
+	if game.SyncFailures >= w.opts.MaxFailures {
+		game.SyncDisabled = true
+		game.NextSyncAt = nil
+		log.Printf("Disabling sync for game '%s' (IGDB ID: %d) after %d consecutive failures", game.Title, game.IGDBID, game.SyncFailures)
 		return
 	}
 
+	next := time.Now().Add(failureBackoff(game.SyncFailures, w.opts.Interval))
+	game.NextSyncAt = &next
+}
+
+func (w *Worker) matchUnmatchedGames(ctx context.Context, games []*model.Game, c *collector) {
 	if len(games) == 0 {
 		return
 	}
 
 	log.Printf("Found %d unmatched games to process", len(games))
 
-	matchedCount := 0
-	multipleCount := 0
-	noMatchCount := 0
-
+	// A user's games are matched one after another so two of them can't claim the
+	// same IGDB ID concurrently; different users run in parallel
+	byUser := make(map[string][]*model.Game)
 	for _, game := range games {
 		// Skip if already marked as needs review
 		if game.MatchStatus == model.MatchStatusNeedsReview {
 			continue
 		}
+		byUser[game.UserID] = append(byUser[game.UserID], game)
+	}
 
-		// Search IGDB for this game title
-		searchResults, err := igdbClient.Search(game.Title)
-		if err != nil {
-			log.Printf("ERROR: Failed to search IGDB for '%s': %v", game.Title, err)
-			continue
-		}
+	userGames := make([][]*model.Game, 0, len(byUser))
+	for _, games := range byUser {
+		userGames = append(userGames, games)
+	}
 
-		// Handle different match scenarios
-		if len(searchResults) == 0 {
-			// No matches found
-			log.Printf("No IGDB matches found for: %s", game.Title)
-			game.MatchStatus = model.MatchStatusNoMatch
-			if saveErr := db.SaveGame(ctx, game); saveErr != nil {
-				log.Printf("ERROR: Failed to update match status for '%s': %v", game.Title, saveErr)
-			}
-			noMatchCount++
-		} else if len(searchResults) == 1 {
-			// Single match - check for duplicates before automatically linking
-			igdbID := searchResults[0].ID
-
-			// Check if this IGDB ID is already used by another game for this user
-			existingGame, err := db.GetGameByIGDBID(ctx, game.UserID, igdbID)
-			if err != nil {
-				log.Printf("ERROR: Failed to check for duplicate IGDB ID %d for '%s': %v", igdbID, game.Title, err)
-				continue
+	runPool(ctx, userGames, w.opts.Concurrency, w.opts.GameTimeout*time.Duration(len(games)), func(ctx context.Context, games []*model.Game) {
+		for _, game := range games {
+			if ctx.Err() != nil {
+				return
 			}
+			gameCtx, cancel := context.WithTimeout(ctx, w.opts.GameTimeout)
+			w.matchGame(gameCtx, game, c)
+			cancel()
+		}
+	})
+}
 
-			if existingGame != nil && existingGame.ID != game.ID {
-				// Another game already has this IGDB ID - mark as needs review
-				log.Printf("IGDB ID %d already used by '%s' - marking '%s' as needs review", igdbID, existingGame.Title, game.Title)
-				game.MatchStatus = model.MatchStatusNeedsReview
-				if saveErr := db.SaveGame(ctx, game); saveErr != nil {
-					log.Printf("ERROR: Failed to update match status for '%s': %v", game.Title, saveErr)
-				}
-				multipleCount++ // Count as needing review
-				continue
-			}
+func (w *Worker) matchGame(ctx context.Context, game *model.Game, c *collector) {
+	// Search IGDB for this game's title, or the user's search hint
+	result, err := w.matcher.Match(ctx, game.UserID, game.ID, game.MatchTitle())
+	if err != nil {
+		log.Printf("ERROR: Failed to match '%s': %v", game.Title, err)
+		c.fail(game, err)
+		return
+	}
 
-			// No duplicate found - fetch details and auto-match
-			igdbGame, err := igdbClient.GetGameByID(igdbID)
-			if err != nil {
-				log.Printf("ERROR: Failed to fetch IGDB details for '%s' (ID: %d): %v", game.Title, igdbID, err)
-				continue
-			}
+	// Keep the best candidates and the reason for the review UI
+	game.MatchCandidates = result.TopCandidates()
+	game.MatchReason = result.Reason
+
+	// Handle different match scenarios
+	switch result.Status {
+	case model.MatchStatusNoMatch:
+		log.Printf("No IGDB matches found for: %s", game.Title)
+		game.MatchStatus = model.MatchStatusNoMatch
+		w.scheduleMatchRetry(game)
+		if saveErr := w.db.SaveGame(ctx, game); saveErr != nil {
+			log.Printf("ERROR: Failed to update match status for '%s': %v", game.Title, saveErr)
+		}
+		c.add(func(r *model.SyncResult) { r.NoMatch++ })
+	case model.MatchStatusNeedsReview:
+		// Another game already has this IGDB ID - mark as needs review
+		log.Printf("IGDB ID %d already used by '%s' - marking '%s' as needs review", result.Match.IGDBID, result.Duplicate.Title, game.Title)
+		game.MatchStatus = model.MatchStatusNeedsReview
+		if saveErr := w.db.SaveGame(ctx, game); saveErr != nil {
+			log.Printf("ERROR: Failed to update match status for '%s': %v", game.Title, saveErr)
+		}
+		c.add(func(r *model.SyncResult) { r.Multiple++ }) // Count as needing review
+	case model.MatchStatusMatched:
+		// Unambiguous match with no duplicate - fetch details and auto-match
+		igdbID := result.Match.IGDBID
+		igdbGame, err := w.igdbClient.GetGameByID(ctx, igdbID)
+		if err != nil {
+			log.Printf("ERROR: Failed to fetch IGDB details for '%s' (ID: %d): %v", game.Title, igdbID, err)
+			c.fail(game, err)
+			return
+		}
 
-			game.IGDBID = igdbID
-			game.MatchStatus = model.MatchStatusMatched
-			api.EnrichGameFromIGDB(game, igdbGame, false)
+		before := *game
+		game.IGDBID = igdbID
+		game.MatchStatus = model.MatchStatusMatched
+		api.EnrichGameFromIGDB(game, igdbGame, false)
+		next := nextSyncAt(game, time.Now(), w.opts.Interval)
+		game.NextSyncAt = &next
 
-			if saveErr := db.SaveGame(ctx, game); saveErr != nil {
-				log.Printf("ERROR: Failed to save matched game '%s': %v", game.Title, saveErr)
-				continue
-			}
+		if saveErr := w.db.SaveGame(ctx, game); saveErr != nil {
+			log.Printf("ERROR: Failed to save matched game '%s': %v", game.Title, saveErr)
+			c.fail(game, saveErr)
+			return
+		}
 
-			log.Printf("Automatically matched: %s -> IGDB ID: %d", game.Title, igdbID)
-			matchedCount++
-		} else {
-			// Multiple matches - mark for user review
-			log.Printf("Multiple IGDB matches found for '%s' (%d results) - marking for review", game.Title, len(searchResults))
-			game.MatchStatus = model.MatchStatusMultiple
-			if saveErr := db.SaveGame(ctx, game); saveErr != nil {
-				log.Printf("ERROR: Failed to update match status for '%s': %v", game.Title, saveErr)
-			}
-			multipleCount++
+		log.Printf("Automatically matched: %s -> IGDB ID: %d (score %.2f)", game.Title, igdbID, result.Match.Score)
+		api.EmitGameEvent(ctx, w.opts.Webhooks, model.EventGameMetadataUpdated, api.GameEventData{Game: game, Changes: api.DiffGames(&before, game)})
+		c.add(func(r *model.SyncResult) { r.Matched++ })
+	default:
+		// Multiple matches - mark for user review
+		log.Printf("Multiple IGDB matches found for '%s' (%d results) - marking for review", game.Title, len(result.Candidates))
+		game.MatchStatus = model.MatchStatusMultiple
+		w.scheduleMatchRetry(game)
+		if saveErr := w.db.SaveGame(ctx, game); saveErr != nil {
+			log.Printf("ERROR: Failed to update match status for '%s': %v", game.Title, saveErr)
 		}
+		c.add(func(r *model.SyncResult) { r.Multiple++ })
 	}
+}
 
-	if matchedCount > 0 || multipleCount > 0 || noMatchCount > 0 {
-		log.Printf("Unmatched game processing complete: %d auto-matched, %d multiple matches, %d no match", matchedCount, multipleCount, noMatchCount)
-	}
+// scheduleMatchRetry backs off a game the matcher couldn't match, so it is retried slowly
+// (IGDB may add the game or an alternative name later) rather than on every run
+func (w *Worker) scheduleMatchRetry(game *model.Game) {
+	game.MatchAttempts++
+	next := time.Now().Add(matchBackoff(game.MatchAttempts))
+	game.NextMatchAt = &next
 }